@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+// movingSpeed is the speed in units/s above which a player counts as moving
+const movingSpeed = 10
+
+// activity counts the sampled ticks a player spent in each state this round
+type activity struct {
+	name                                               string
+	steamID                                            uint64
+	ticks                                              int
+	alive, moving, crouched, scoped, airborne, walking int
+	// scopedSeconds adds up the game time between samples spent scoped, so
+	// it doesn't depend on -interval-ms
+	scopedSeconds float64
+	lastTick      int
+}
+
+var (
+	exportActivity bool
+	activityFile   io.WriteCloser
+	activityWriter recordWriter
+	activities     = map[string]*activity{}
+	// activityOrder keeps rows in the order players were first seen
+	activityOrder []string
+)
+
+func registerActivityHandlers(p dem.Parser) {
+	activityFile, activityWriter = openCSV(outputPath("activity_summary.csv"), []string{
+		"round", "player", "steamid", "ticks",
+		"alive", "moving", "crouched", "scoped", "airborne", "walking",
+		"scoped_time_seconds",
+	})
+
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		clear(activities)
+		activityOrder = nil
+	})
+
+	p.RegisterEventHandler(func(e events.RoundEnd) {
+		if skippingRound || currentRound == 0 {
+			return
+		}
+		for _, key := range activityOrder {
+			writeActivity(activities[key])
+		}
+	})
+}
+
+// trackActivity adds the sampled tick to every player's round totals
+func trackActivity(ctx tickContext, players []*common.Player) {
+	if currentRound == 0 {
+		return
+	}
+	for _, player := range players {
+		key := playerKey(player)
+		a, ok := activities[key]
+		if !ok {
+			a = &activity{name: player.Name, steamID: player.SteamID64, lastTick: ctx.tick - 1}
+			activities[key] = a
+			activityOrder = append(activityOrder, key)
+		}
+		elapsed := ctx.tick - a.lastTick
+		a.lastTick = ctx.tick
+
+		a.ticks++
+		if !player.IsAlive() {
+			continue
+		}
+		a.alive++
+		if player.Velocity().Norm() > movingSpeed {
+			a.moving++
+		}
+		if player.IsDucking() {
+			a.crouched++
+		}
+		if player.IsScoped() {
+			a.scoped++
+			a.scopedSeconds += float64(elapsed) / ctx.rate
+		}
+		if player.IsAirborne() {
+			a.airborne++
+		}
+		if player.IsWalking() {
+			a.walking++
+		}
+	}
+}
+
+// writeActivity writes the fraction of the round's sampled ticks spent in
+// each state
+func writeActivity(a *activity) {
+	fraction := func(n int) string {
+		return fmt.Sprintf("%.3f", float64(n)/float64(a.ticks))
+	}
+	activityWriter.Write([]string{
+		strconv.Itoa(currentRound),
+		a.name,
+		strconv.FormatUint(a.steamID, 10),
+		strconv.Itoa(a.ticks),
+		fraction(a.alive),
+		fraction(a.moving),
+		fraction(a.crouched),
+		fraction(a.scoped),
+		fraction(a.airborne),
+		fraction(a.walking),
+		fmt.Sprintf("%.2f", a.scopedSeconds),
+	})
+}
@@ -0,0 +1,24 @@
+package main
+
+import "flag"
+
+// eventFlags are the exporters -all-events turns on
+var eventFlags = []string{
+	"kills", "damage", "grenades", "opening-kills", "items", "movement-events",
+	"zoom-events", "bomb", "rounds-csv", "scoreboard", "economy",
+	"utility-damage", "phase-events", "infernos", "text-log",
+	"activity-summary", "spotted", "visibility", "team-aggregate-positions",
+	"reactions",
+}
+
+// applyAllEvents sets every event exporter flag that wasn't given on the
+// command line, so single exporters can still be left out with e.g.
+// -all-events -kills=false.
+func applyAllEvents(fs *flag.FlagSet) {
+	given := givenFlags(fs)
+	for _, name := range eventFlags {
+		if !given[name] {
+			fs.Set(name, "true")
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/golang/geo/r3"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+// angleOnly swaps the tick export for angleHeader, for crosshair placement
+// datasets that don't need positions.
+var angleOnly bool
+
+var angleHeader = []string{"tick", "steamid", "yaw", "pitch", "nearest_enemy_angle_diff"}
+
+// angleValues is the -angle-only counterpart of playerValues
+func angleValues(ctx tickContext, player *common.Player) []string {
+	yaw, pitch := player.ViewDirectionX(), player.ViewDirectionY()
+	if interpolate {
+		pose := interpolatedPose(ctx, player, poseOf(player))
+		yaw, pitch = pose.viewX, pose.viewY
+	}
+
+	var diff string
+	if n, ok := ctx.nearest[player]; ok {
+		toEnemy := n.enemy.PositionEyes().Sub(player.PositionEyes())
+		diff = fmt.Sprintf("%.2f", angleBetween(viewVector(yaw, pitch), toEnemy))
+	}
+
+	return []string{
+		strconv.Itoa(ctx.tick),
+		strconv.FormatUint(player.SteamID64, 10),
+		fmt.Sprintf("%.4f", yaw),
+		fmt.Sprintf("%.4f", pitch),
+		diff,
+	}
+}
+
+// viewVector turns yaw and pitch in degrees into a unit vector. Positive
+// pitch looks down in the Source engine.
+func viewVector(yaw, pitch float32) r3.Vector {
+	y := float64(yaw) * math.Pi / 180
+	p := float64(pitch) * math.Pi / 180
+	return r3.Vector{
+		X: math.Cos(p) * math.Cos(y),
+		Y: math.Cos(p) * math.Sin(y),
+		Z: -math.Sin(p),
+	}
+}
+
+// angleBetween returns the angle between two vectors in degrees
+func angleBetween(a, b r3.Vector) float64 {
+	if a.Norm() == 0 || b.Norm() == 0 {
+		return 0
+	}
+	cos := a.Dot(b) / (a.Norm() * b.Norm())
+	return math.Acos(math.Max(-1, math.Min(1, cos))) * 180 / math.Pi
+}
+
+// aimThreshold is how many degrees off an enemy the view may be for
+// aiming_at_enemy
+var aimThreshold float64
+
+// aimingAtEnemy reports whether player's view is within aimThreshold of any
+// living enemy. Walls and smokes are ignored, so an enemy behind cover
+// counts too.
+func aimingAtEnemy(ctx tickContext, player *common.Player) bool {
+	if !player.IsAlive() {
+		return false
+	}
+	view := viewVector(player.ViewDirectionX(), player.ViewDirectionY())
+	eyes := player.PositionEyes()
+	for _, enemy := range ctx.players {
+		if !enemy.IsAlive() || !isEnemy(player, enemy) {
+			continue
+		}
+		if angleBetween(view, enemy.PositionEyes().Sub(eyes)) <= aimThreshold {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+// States of the C4 reported in bomb_pos.csv. Carried and dropped are read
+// off the bomb each tick, the rest are latched by events until the next round.
+const (
+	bombCarried  = "carried"
+	bombDropped  = "dropped"
+	bombPlanted  = "planted"
+	bombDefused  = "defused"
+	bombExploded = "exploded"
+)
+
+var (
+	exportBomb bool
+	bombFile   io.WriteCloser
+	bombWriter recordWriter
+	// bombLatched is the event driven state, empty while the bomb is in play
+	bombLatched string
+)
+
+func registerBombHandlers(p dem.Parser) {
+	bombFile, bombWriter = openCSV(outputPath("bomb_pos.csv"), []string{
+		"tick", "game_time_seconds", "round", "state", "carrier",
+		"pos_x", "pos_y", "pos_z",
+	})
+
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		bombLatched = ""
+	})
+	p.RegisterEventHandler(func(e events.BombPlanted) {
+		bombLatched = bombPlanted
+	})
+	p.RegisterEventHandler(func(e events.BombDefused) {
+		bombLatched = bombDefused
+	})
+	p.RegisterEventHandler(func(e events.BombExplode) {
+		bombLatched = bombExploded
+	})
+}
+
+// trackBomb writes the C4 position for the sampled tick
+func trackBomb(ctx tickContext, gs dem.GameState) {
+	bomb := gs.Bomb()
+	if bomb == nil {
+		return
+	}
+
+	state := bombLatched
+	if state == "" {
+		state = pick(bomb.Carrier != nil, bombCarried, bombDropped)
+	}
+	var carrier string
+	if state == bombCarried {
+		carrier = playerName(bomb.Carrier)
+	}
+
+	pos := bomb.Position()
+	bombWriter.Write([]string{
+		strconv.Itoa(ctx.tick),
+		formatSeconds(ctx.gameTime),
+		strconv.Itoa(currentRound),
+		state,
+		carrier,
+		fmt.Sprintf("%.2f", pos.X),
+		fmt.Sprintf("%.2f", pos.Y),
+		fmt.Sprintf("%.2f", pos.Z),
+	})
+}
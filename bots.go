@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	// botOwners maps a bot filling a disconnected human's slot to that human
+	botOwners = map[string]uint64{}
+	// orphanedSlots are humans per side who left and haven't been replaced
+	orphanedSlots = map[common.Team][]uint64{}
+)
+
+// registerBotHandlers follows humans leaving mid-match and the bots that
+// join their side afterwards, which are taken to fill their slot.
+func registerBotHandlers(p dem.Parser) {
+	p.RegisterEventHandler(func(e events.PlayerDisconnected) {
+		if e.Player == nil || e.Player.IsBot || e.Player.SteamID64 == 0 {
+			return
+		}
+		team := e.Player.Team
+		orphanedSlots[team] = append(orphanedSlots[team], e.Player.SteamID64)
+	})
+
+	p.RegisterEventHandler(func(e events.PlayerTeamChange) {
+		if !e.IsBot || e.Player == nil {
+			return
+		}
+		if queue := orphanedSlots[e.NewTeam]; len(queue) > 0 {
+			botOwners[playerKey(e.Player)] = queue[0]
+			orphanedSlots[e.NewTeam] = queue[1:]
+		}
+	})
+
+	// A human coming back gets their slot back from the bot
+	p.RegisterEventHandler(func(e events.PlayerConnect) {
+		if e.Player == nil || e.Player.IsBot {
+			return
+		}
+		id := e.Player.SteamID64
+		for team, queue := range orphanedSlots {
+			for i, owner := range queue {
+				if owner == id {
+					orphanedSlots[team] = append(queue[:i:i], queue[i+1:]...)
+					break
+				}
+			}
+		}
+		for key, owner := range botOwners {
+			if owner == id {
+				delete(botOwners, key)
+			}
+		}
+	})
+}
+
+// botTakeoverColumns returns is_bot_takeover and original_steamid. A bot is
+// a takeover while a human controls it or while it fills a disconnected
+// human's slot; original_steamid is then that human. Humans controlling a
+// bot are flagged as well, and everyone else is their own original.
+func botTakeoverColumns(ctx tickContext, player *common.Player) (string, string) {
+	if !player.IsBot {
+		return boolToIntString(player.IsControllingBot()), strconv.FormatUint(player.SteamID64, 10)
+	}
+
+	for _, other := range ctx.players {
+		if !other.IsBot && other.ControlledBot() == player {
+			return "1", strconv.FormatUint(other.SteamID64, 10)
+		}
+	}
+	if owner, ok := botOwners[playerKey(player)]; ok {
+		return "1", strconv.FormatUint(owner, 10)
+	}
+	return "0", ""
+}
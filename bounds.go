@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/golang/geo/r3"
+)
+
+// bounds is an axis-aligned box in world coordinates
+type bounds struct {
+	min, max r3.Vector
+}
+
+var (
+	clampBounds     *bounds
+	boundsMode      string
+	outOfBoundsRows int
+)
+
+// parseBounds parses "minx,miny,minz,maxx,maxy,maxz"
+func parseBounds(s string) (*bounds, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 6 {
+		return nil, fmt.Errorf("expected 6 comma separated values, got %d", len(parts))
+	}
+
+	var v [6]float64
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q: %w", part, err)
+		}
+		v[i] = f
+	}
+
+	b := &bounds{min: r3.Vector{X: v[0], Y: v[1], Z: v[2]}, max: r3.Vector{X: v[3], Y: v[4], Z: v[5]}}
+	if b.min.X > b.max.X || b.min.Y > b.max.Y || b.min.Z > b.max.Z {
+		return nil, fmt.Errorf("minimum is larger than maximum")
+	}
+	return b, nil
+}
+
+func (b *bounds) contains(v r3.Vector) bool {
+	return v.X >= b.min.X && v.X <= b.max.X &&
+		v.Y >= b.min.Y && v.Y <= b.max.Y &&
+		v.Z >= b.min.Z && v.Z <= b.max.Z
+}
+
+func (b *bounds) clamp(v r3.Vector) r3.Vector {
+	return r3.Vector{
+		X: math.Min(math.Max(v.X, b.min.X), b.max.X),
+		Y: math.Min(math.Max(v.Y, b.min.Y), b.max.Y),
+		Z: math.Min(math.Max(v.Z, b.min.Z), b.max.Z),
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+// calloutRegion is one named box from a -callout-regions file. Z limits are
+// optional so most regions only need the ground plan.
+type calloutRegion struct {
+	Name string   `json:"name"`
+	MinX float64  `json:"min_x"`
+	MinY float64  `json:"min_y"`
+	MaxX float64  `json:"max_x"`
+	MaxY float64  `json:"max_y"`
+	MinZ *float64 `json:"min_z"`
+	MaxZ *float64 `json:"max_z"`
+}
+
+func (r calloutRegion) contains(x, y, z float64) bool {
+	if x < r.MinX || x > r.MaxX || y < r.MinY || y > r.MaxY {
+		return false
+	}
+	return (r.MinZ == nil || z >= *r.MinZ) && (r.MaxZ == nil || z <= *r.MaxZ)
+}
+
+// placeLabels renames the place names of the official maps' nav meshes that
+// don't read well once split at capitals
+var placeLabels = map[string]string{
+	"BombsiteA": "A Site",
+	"BombsiteB": "B Site",
+	"BombsiteC": "C Site",
+	"CTSpawn":   "CT Spawn",
+	"TSpawn":    "T Spawn",
+	"Middle":    "Mid",
+	"TopofMid":  "Top Mid",
+	"BackAlley": "Back Alley",
+}
+
+// calloutColumns adds the location column for -callouts. Regions from
+// -callout-regions are checked first, in file order; maps without any fall
+// back to the place name the game stores for the player's nav area, which
+// every official map has.
+type calloutColumns struct {
+	regions map[string][]calloutRegion
+	current []calloutRegion
+}
+
+var (
+	callouts *calloutColumns
+	// calloutsReady is cleared per demo so the next one picks its own map
+	calloutsReady bool
+)
+
+func loadCalloutRegions(path string) (map[string][]calloutRegion, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var regions map[string][]calloutRegion
+	if err := json.Unmarshal(data, &regions); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return regions, nil
+}
+
+// useMap selects the custom regions for the demo's map, if any
+func (c *calloutColumns) useMap(mapName string) {
+	c.current = c.regions[baseMapName(mapName)]
+	calloutsReady = true
+}
+
+func (c *calloutColumns) Headers() []string {
+	return []string{"location"}
+}
+
+func (c *calloutColumns) Values(tick int, p *common.Player) []string {
+	if c.current != nil {
+		pos := p.Position()
+		for _, r := range c.current {
+			if r.contains(pos.X, pos.Y, pos.Z) {
+				return []string{r.Name}
+			}
+		}
+		return []string{""}
+	}
+	return []string{placeLabel(p.LastPlaceName())}
+}
+
+// placeLabel turns a nav place name like "LongDoors" into "Long Doors"
+func placeLabel(place string) string {
+	if label, ok := placeLabels[place]; ok {
+		return label
+	}
+	var b strings.Builder
+	for i, r := range place {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
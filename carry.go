@@ -0,0 +1,47 @@
+package main
+
+import (
+	"github.com/golang/geo/r3"
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+// playerPose is the part of a player's state that is carried past death
+type playerPose struct {
+	pos          r3.Vector
+	viewX, viewY float32
+}
+
+var (
+	carryDead     bool
+	lastAlivePose = map[string]playerPose{}
+)
+
+func poseOf(player *common.Player) playerPose {
+	return playerPose{
+		pos:   player.Position(),
+		viewX: player.ViewDirectionX(),
+		viewY: player.ViewDirectionY(),
+	}
+}
+
+func registerCarryHandlers(p dem.Parser) {
+	// Everyone respawns, so death positions don't carry into the next round
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		clear(lastAlivePose)
+	})
+}
+
+// carryPose remembers the pose of living players and hands it back for dead
+// ones, so the dead keep their death position until the round is over.
+func carryPose(player *common.Player, alive bool, pose playerPose) playerPose {
+	if alive {
+		lastAlivePose[playerKey(player)] = pose
+		return pose
+	}
+	if last, ok := lastAlivePose[playerKey(player)]; ok {
+		return last
+	}
+	return pose
+}
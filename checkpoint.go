@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The checkpoint file has one "<sha256>\t<absolute demo path>" line per demo
+// that was exported successfully.
+
+func checkpointKey(hash, demoPath string) string {
+	if abs, err := filepath.Abs(demoPath); err == nil {
+		demoPath = abs
+	}
+	return hash + "\t" + demoPath
+}
+
+// loadCheckpoint reads the demos completed by earlier runs. A missing file
+// just means nothing has been exported yet.
+func loadCheckpoint(path string) (map[string]bool, error) {
+	done := map[string]bool{}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			done[line] = true
+		}
+	}
+	return done, scanner.Err()
+}
+
+// appendCheckpoint records a finished demo. The file is reopened for every
+// demo so a crash never loses the demos completed before it.
+func appendCheckpoint(path, key string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(f, key); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func fileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
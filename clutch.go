@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+// clutch is the moment a round became a 1vX: the first tick one side was
+// down to a single living player facing at least two enemies
+type clutch struct {
+	tick      int
+	player    *common.Player
+	opponents int
+}
+
+// trackClutch runs on every frame of a live round, so the start tick is
+// exact even when the tick export is sampled
+func trackClutch(p dem.Parser) {
+	if round.clutch.player != nil || (roundPhase != phaseLive && roundPhase != phasePostPlant) {
+		return
+	}
+	gs := p.GameState()
+	ct := alivePlayers(gs.TeamCounterTerrorists())
+	t := alivePlayers(gs.TeamTerrorists())
+	switch {
+	case len(ct) == 1 && len(t) >= 2:
+		round.clutch = clutch{gs.IngameTick(), ct[0], len(t)}
+	case len(t) == 1 && len(ct) >= 2:
+		round.clutch = clutch{gs.IngameTick(), t[0], len(ct)}
+	}
+}
+
+func alivePlayers(team *common.TeamState) []*common.Player {
+	if team == nil {
+		return nil
+	}
+	var alive []*common.Player
+	for _, player := range team.Members() {
+		if player.IsAlive() {
+			alive = append(alive, player)
+		}
+	}
+	return alive
+}
+
+// columns returns clutch_start_tick, clutch_start_seconds, clutch_steamid
+// and clutch_opponents, all empty for rounds without a clutch
+func (c clutch) columns(rate float64) []string {
+	if c.player == nil {
+		return []string{"", "", "", ""}
+	}
+	return []string{
+		strconv.Itoa(c.tick),
+		formatSeconds(gameTimeSeconds(c.tick, rate)),
+		playerSteamID(c.player),
+		strconv.Itoa(c.opponents),
+	}
+}
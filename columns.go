@@ -0,0 +1,71 @@
+package main
+
+import "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+
+// ColumnProvider adds columns to the tick export. Forks can plug in their own
+// from an init function with RegisterColumnProvider instead of patching
+// writePlayerData; their columns come after the built-in ones.
+type ColumnProvider interface {
+	// Headers names the provider's columns. It's called once per export.
+	Headers() []string
+	// Values returns one value per header for player on tick. Returning nil
+	// drops the player's row for that tick.
+	Values(tick int, p *common.Player) []string
+}
+
+// builtinColumns is the default provider holding the exporter's own columns.
+// Its header is set up by runExport once flags like -deltas have applied.
+type builtinColumns struct {
+	header []string
+	ctx    tickContext
+}
+
+func (c *builtinColumns) Headers() []string {
+	return c.header
+}
+
+func (c *builtinColumns) Values(tick int, p *common.Player) []string {
+	if positionsOnly {
+		return positionValues(c.ctx, p)
+	}
+	if angleOnly {
+		return angleValues(c.ctx, p)
+	}
+	return playerValues(c.ctx, p)
+}
+
+var (
+	builtin         = &builtinColumns{}
+	columnProviders = []ColumnProvider{builtin}
+)
+
+// RegisterColumnProvider appends cp to the providers composing the tick rows
+func RegisterColumnProvider(cp ColumnProvider) {
+	columnProviders = append(columnProviders, cp)
+}
+
+// composeHeader makes header the built-in columns and returns the full tick
+// header with every provider's columns.
+func composeHeader(header []string) []string {
+	builtin.header = header
+	var all []string
+	for _, cp := range columnProviders {
+		all = append(all, cp.Headers()...)
+	}
+	return all
+}
+
+// providerValues collects the row for player from every provider, or nil if
+// any of them dropped it.
+func providerValues(ctx tickContext, player *common.Player) []string {
+	builtin.ctx = ctx
+	var row []string
+	for _, cp := range columnProviders {
+		values := cp.Values(ctx.tick, player)
+		if values == nil {
+			return nil
+		}
+		row = append(row, values...)
+	}
+	return row
+}
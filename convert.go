@@ -0,0 +1,127 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// optionalColumns are tick columns added by flags that have no TickRecord
+// field; converted to protobuf they go to the extra map
+var optionalColumns = map[string]bool{
+	"location":         true,
+	"dist_to_bomb":     true,
+	"dist_to_a":        true,
+	"dist_to_b":        true,
+	"time_since_plant": true,
+}
+
+// runConvert rewrites an existing tick CSV in another -format without
+// parsing the demo again
+func runConvert(args []string) {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	in := fs.String("in", "", "Tick CSV to convert (.csv or .csv.gz)")
+	out := fs.String("out", "", "File (or s3://, gs:// URL) to write")
+	fs.StringVar(&outputFormat, "format", "protobuf", "Format to write: protobuf or csv")
+	fs.StringVar(&nullToken, "null-token", "", "The -null-token the CSV was exported with, read back as missing values")
+	allowExtra := fs.Bool("allow-extra", false, "If true, accept columns the exporter doesn't know (e.g. from -roster); protobuf puts them in the extra map")
+	fs.Parse(args)
+
+	if *in == "" || *out == "" {
+		log.Fatalf("❌ convert needs -in and -out")
+	}
+	if outputFormat != "csv" && outputFormat != "protobuf" {
+		log.Fatalf("❌ Unknown format %q (expected csv or protobuf)", outputFormat)
+	}
+
+	f, err := os.Open(*in)
+	if err != nil {
+		log.Fatalf("❌ Failed to open %s: %v", *in, err)
+	}
+	defer f.Close()
+	var src io.Reader = f
+	if strings.HasSuffix(*in, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			log.Fatalf("❌ Failed to read %s: %v", *in, err)
+		}
+		src = zr
+	}
+
+	reader := csv.NewReader(src)
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err != nil {
+		log.Fatalf("❌ Failed to read the header of %s: %v", *in, err)
+	}
+	header = append([]string(nil), header...)
+	if err := checkTickHeader(header, *allowExtra); err != nil {
+		log.Fatalf("❌ %s: %v", *in, err)
+	}
+
+	file, err := createOutput(*out)
+	if err != nil {
+		log.Fatalf("❌ Failed to create %s: %v", *out, err)
+	}
+	var writer recordWriter
+	if outputFormat == "protobuf" {
+		writer = newProtoWriter(file, header)
+	} else {
+		w := csv.NewWriter(file)
+		w.Write(header)
+		writer = w
+	}
+
+	rows := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("❌ %s: %v", *in, err)
+		}
+		if nullToken != "" {
+			for i, value := range record {
+				if value == nullToken {
+					record[i] = ""
+				}
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			log.Fatalf("❌ Row %d of %s: %v", rows+2, *in, err)
+		}
+		rows++
+	}
+	writer.Flush()
+	if err := file.Close(); err != nil {
+		log.Fatalf("❌ Failed to finalize %s: %v", *out, err)
+	}
+	fmt.Printf("✅ Converted %d rows to %s\n", rows, *out)
+}
+
+// checkTickHeader makes sure header is a tick file's: it starts with tick
+// and, unless allowExtra, only has columns the exporter can write
+func checkTickHeader(header []string, allowExtra bool) error {
+	if len(header) == 0 || header[0] != "tick" {
+		return fmt.Errorf("not a tick file, the first column should be tick")
+	}
+	if allowExtra {
+		return nil
+	}
+	var unknown []string
+	for _, col := range header {
+		if _, ok := tickFields[col]; !ok && !optionalColumns[col] {
+			unknown = append(unknown, col)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown columns %s (pass -allow-extra to keep them anyway)", strings.Join(unknown, ", "))
+	}
+	return nil
+}
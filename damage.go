@@ -0,0 +1,60 @@
+package main
+
+import (
+	"io"
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	exportDamage   bool
+	teamDamageOnly bool
+	damageFile     io.WriteCloser
+	damageWriter   recordWriter
+)
+
+func registerDamageHandlers(p dem.Parser) {
+	damageFile, damageWriter = openCSV(outputPath("damage.csv"), []string{
+		"tick", "game_time_seconds", "round",
+		"attacker", "attacker_steamid", "victim", "victim_steamid",
+		"weapon", "health_damage", "armor_damage", "victim_health",
+	})
+
+	p.RegisterEventHandler(func(e events.PlayerHurt) {
+		if skippingRound || e.Player == nil {
+			return
+		}
+		if teamDamageOnly && !isTeamDamage(e.Attacker, e.Player) {
+			return
+		}
+
+		tick := p.GameState().IngameTick()
+		weapon := e.WeaponString
+		if e.Weapon != nil {
+			weapon = e.Weapon.String()
+		}
+
+		damageWriter.Write([]string{
+			strconv.Itoa(tick),
+			formatSeconds(gameTimeSeconds(tick, tickRate(p))),
+			strconv.Itoa(currentRound),
+			playerName(e.Attacker),
+			playerSteamID(e.Attacker),
+			e.Player.Name,
+			playerSteamID(e.Player),
+			weapon,
+			strconv.Itoa(e.HealthDamageTaken),
+			strconv.Itoa(e.ArmorDamageTaken),
+			strconv.Itoa(e.Health),
+		})
+	})
+}
+
+// isTeamDamage reports whether attacker hurt a teammate. Self damage (fall
+// damage, own grenades) and world damage don't count.
+func isTeamDamage(attacker, victim *common.Player) bool {
+	return attacker != nil && victim != nil && attacker != victim && attacker.Team == victim.Team
+}
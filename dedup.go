@@ -0,0 +1,48 @@
+package main
+
+import "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+
+// dedupBy picks which FrameDone events get rows: "tick" writes the first
+// frame of every tick, "frame" every parser frame that changed a player's
+// position or view (CS2 sub-tick demos have several frames per tick; the
+// frame column tells them apart) and "none" every FrameDone.
+var (
+	dedupBy = "tick"
+	// framePoses are the poses of the last frame written under -dedup-by
+	// frame
+	framePoses = map[string]playerPose{}
+)
+
+// duplicateFrame reports whether a FrameDone for tick repeats one already
+// written under -dedup-by
+func duplicateFrame(tick int, players []*common.Player) bool {
+	switch dedupBy {
+	case "tick":
+		if tick == lastTick {
+			return true
+		}
+	case "frame":
+		if tick == lastTick && samePoses(players) {
+			return true
+		}
+		clear(framePoses)
+		for _, player := range players {
+			framePoses[playerKey(player)] = poseOf(player)
+		}
+	}
+	lastTick = tick
+	return false
+}
+
+// samePoses reports whether every player is where framePoses left them
+func samePoses(players []*common.Player) bool {
+	if len(players) != len(framePoses) {
+		return false
+	}
+	for _, player := range players {
+		if pose, ok := framePoses[playerKey(player)]; !ok || pose != poseOf(player) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"github.com/golang/geo/r3"
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	positionDeltas bool
+	lastPositions  = map[string]r3.Vector{}
+)
+
+// deltaHeader renames the position columns for -deltas
+func deltaHeader(header []string) []string {
+	renamed := map[string]string{"pos_x": "dx", "pos_y": "dy", "pos_z": "dz"}
+	out := make([]string, len(header))
+	for i, col := range header {
+		if name, ok := renamed[col]; ok {
+			col = name
+		}
+		out[i] = col
+	}
+	return out
+}
+
+func registerDeltaHandlers(p dem.Parser) {
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		clear(lastPositions)
+	})
+}
+
+// positionDelta returns how far the player moved since their previous sampled
+// tick. The first sample of a round has nothing to compare against, so it is
+// the absolute position and serves as the reference for the deltas after it.
+func positionDelta(key string, pos r3.Vector) r3.Vector {
+	prev, ok := lastPositions[key]
+	lastPositions[key] = pos
+	if !ok {
+		return pos
+	}
+	return pos.Sub(prev)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"io"
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	exportEconomy bool
+	economyFile   io.WriteCloser
+	economyWriter recordWriter
+	lastMoney     = map[string]int{}
+	// moneyReasons explains the next money increase of a player. The game
+	// doesn't say why money changed, so events leave a hint that the diff
+	// picks up once the new balance arrives.
+	moneyReasons = map[string]string{}
+)
+
+func registerEconomyHandlers(p dem.Parser) {
+	economyFile, economyWriter = openCSV(outputPath("economy.csv"), []string{
+		"tick", "game_time_seconds", "round", "player", "steamid", "delta", "reason", "balance",
+	})
+
+	// Win/loss bonuses are paid out as the next round starts
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		clear(moneyReasons)
+		for _, player := range p.GameState().Participants().Playing() {
+			moneyReasons[playerKey(player)] = "round_income"
+		}
+	})
+	p.RegisterEventHandler(func(e events.Kill) {
+		if e.Killer != nil {
+			moneyReasons[playerKey(e.Killer)] = "kill_reward"
+		}
+	})
+	p.RegisterEventHandler(func(e events.BombPlanted) {
+		if e.Player != nil {
+			moneyReasons[playerKey(e.Player)] = "plant_bonus"
+		}
+	})
+	p.RegisterEventHandler(func(e events.BombDefused) {
+		if e.Player != nil {
+			moneyReasons[playerKey(e.Player)] = "defuse_bonus"
+		}
+	})
+}
+
+// trackEconomy diffs every player's money against the previous sampled tick
+// and writes a row per change. Decreases are purchases; increases take the
+// reason hinted by the last event, or "other".
+func trackEconomy(ctx tickContext, players []*common.Player) {
+	if currentRound == 0 {
+		return
+	}
+	for _, player := range players {
+		key := playerKey(player)
+		money := player.Money()
+		prev, seen := lastMoney[key]
+		lastMoney[key] = money
+		if !seen || money == prev {
+			continue
+		}
+
+		delta := money - prev
+		reason := "purchase"
+		if delta > 0 {
+			reason = moneyReasons[key]
+			if reason == "" {
+				reason = "other"
+			}
+			delete(moneyReasons, key)
+		}
+
+		economyWriter.Write([]string{
+			strconv.Itoa(ctx.tick),
+			formatSeconds(gameTimeSeconds(ctx.tick, ctx.rate)),
+			strconv.Itoa(currentRound),
+			player.Name,
+			strconv.FormatUint(player.SteamID64, 10),
+			strconv.Itoa(delta),
+			reason,
+			strconv.Itoa(money),
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Errors returned by exportDemo, for callers embedding the exporter. Use
+// errors.Is to tell them apart; the wrapped error has the details.
+var (
+	ErrDemoOpen = errors.New("failed to open demo")
+	ErrParse    = errors.New("failed to parse demo")
+	ErrWrite    = errors.New("failed to write output")
+	ErrConfig   = errors.New("invalid configuration for demo")
+)
+
+// outputErr is the first output (or per-demo setup) failure of the current
+// demo. Outputs are opened and written from inside event handlers, which
+// can't return errors, so they record it here and parsing is stopped at the
+// next frame.
+var outputErr error
+
+func failOutput(what string, err error) {
+	if outputErr == nil {
+		outputErr = fmt.Errorf("%w: %s: %v", ErrWrite, what, err)
+	}
+}
+
+// discardOutput stands in for an output that failed to open, so writers
+// have somewhere to go until parsing stops
+type discardOutput struct{}
+
+func (discardOutput) Write(b []byte) (int, error) { return len(b), nil }
+func (discardOutput) Close() error                { return nil }
+
+// openOutput is createOutput for files opened while parsing
+func openOutput(path string) io.WriteCloser {
+	file, err := createOutput(path)
+	if err != nil {
+		failOutput(path, err)
+		return discardOutput{}
+	}
+	return file
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportDemoMissingFile(t *testing.T) {
+	resetDemoState()
+	err := exportDemo([]string{filepath.Join(t.TempDir(), "missing.dem")}, t.TempDir())
+	if !errors.Is(err, ErrDemoOpen) {
+		t.Fatalf("err = %v, want ErrDemoOpen", err)
+	}
+}
+
+func TestExportDemoGarbageFile(t *testing.T) {
+	demo := filepath.Join(t.TempDir(), "garbage.dem")
+	if err := os.WriteFile(demo, []byte("this is not a demo file"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	resetDemoState()
+	err := exportDemo([]string{demo}, t.TempDir())
+	if !errors.Is(err, ErrParse) {
+		t.Fatalf("err = %v, want ErrParse", err)
+	}
+}
+
+func TestExportDemoUnwritableOutput(t *testing.T) {
+	dir := t.TempDir()
+	demo := filepath.Join(dir, "match.dem")
+	if err := os.WriteFile(demo, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A file where the output folder's parent should be can't be created
+	// into, not even by root
+	blocker := filepath.Join(dir, "out")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	resetDemoState()
+	err := exportDemo([]string{demo}, blocker)
+	if !errors.Is(err, ErrWrite) {
+		t.Fatalf("err = %v, want ErrWrite", err)
+	}
+}
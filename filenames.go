@@ -0,0 +1,45 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+)
+
+var (
+	tickFilename  string
+	roundFilename string
+	demoName      string
+)
+
+// renderFilename fills in the placeholders supported by -tick-filename and
+// -round-filename: {demo}, {map}, {tickrate}, {round} and {ext} (the
+// -format extension).
+func renderFilename(tmpl string, p dem.Parser, round int) string {
+	return strings.NewReplacer(
+		"{demo}", demoName,
+		// Workshop maps are workshop/<id>/<name>, which would add folders
+		"{map}", baseMapName(p.Header().MapName),
+		"{tickrate}", strconv.Itoa(int(math.Round(tickRate(p)))),
+		"{round}", strconv.Itoa(round),
+		"{ext}", tickFileExt(),
+	).Replace(tmpl)
+}
+
+// withTickRate adds the map and tick rate in front of the extension of a
+// filename template that doesn't use {tickrate} yet, for -tick-rate-in-filename
+func withTickRate(tmpl string) string {
+	if strings.Contains(tmpl, "{tickrate}") {
+		return tmpl
+	}
+	suffix := "_{tickrate}"
+	if !strings.Contains(tmpl, "{map}") {
+		suffix = "_{map}" + suffix
+	}
+	if i := strings.Index(tmpl, "{ext}"); i >= 0 {
+		return tmpl[:i] + suffix + tmpl[i:]
+	}
+	return tmpl + suffix
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+// flashedBy maps a blinded player's playerKey to whoever flashed them last
+var flashedBy = map[string]*common.Player{}
+
+func registerFlashHandlers(p dem.Parser) {
+	p.RegisterEventHandler(func(e events.PlayerFlashed) {
+		if e.Player == nil {
+			return
+		}
+		flashedBy[playerKey(e.Player)] = e.Attacker
+	})
+}
+
+// flasherOf returns who blinded player, or "" once the flash has worn off
+func flasherOf(player *common.Player) string {
+	if !player.IsBlinded() {
+		delete(flashedBy, playerKey(player))
+		return ""
+	}
+	return playerName(flashedBy[playerKey(player)])
+}
@@ -0,0 +1,37 @@
+package main
+
+import "io"
+
+// fsyncRounds makes closeCurrentRound and closePlayerFiles fsync each round
+// file, so a crash later in a long batch can't lose rounds that were
+// already complete
+var fsyncRounds bool
+
+type syncer interface {
+	Sync() error
+}
+
+// syncOutput flushes writer and fsyncs file if it is a local file (bucket
+// uploads are only durable once they are finalized on close anyway)
+func syncOutput(file io.WriteCloser, writer recordWriter) {
+	if writer != nil {
+		writer.Flush()
+	}
+	if s, ok := file.(syncer); ok {
+		if err := s.Sync(); err != nil {
+			failOutput("fsync", err)
+		}
+	}
+}
+
+// Sync pushes everything compressed so far to the file and fsyncs it. Only
+// the gzip trailer written on Close is missing until then.
+func (g *gzipFile) Sync() error {
+	if err := g.Writer.Flush(); err != nil {
+		return err
+	}
+	if s, ok := g.file.(syncer); ok {
+		return s.Sync()
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	exportGrenades bool
+	grenadesFile   io.WriteCloser
+	grenadesWriter recordWriter
+)
+
+// grenadeTypes names the grenade_type of each throwable. Molotovs and
+// incendiaries share a projectile entity, so the type comes from the weapon.
+var grenadeTypes = map[common.EquipmentType]string{
+	common.EqHE:         "HE",
+	common.EqFlash:      "Flash",
+	common.EqSmoke:      "Smoke",
+	common.EqDecoy:      "Decoy",
+	common.EqMolotov:    "Molotov",
+	common.EqIncendiary: "Incendiary",
+}
+
+func registerGrenadeHandlers(p dem.Parser) {
+	grenadesFile, grenadesWriter = openCSV(outputPath("grenades.csv"), []string{
+		"tick", "game_time_seconds", "round",
+		"thrower", "thrower_steamid", "side", "grenade_type",
+		"x", "y", "z",
+	})
+
+	p.RegisterEventHandler(func(e events.GrenadeProjectileThrow) {
+		g := e.Projectile
+		if skippingRound || g == nil || g.WeaponInstance == nil {
+			return
+		}
+		grenadeType, ok := grenadeTypes[g.WeaponInstance.Type]
+		if !ok {
+			grenadeType = g.WeaponInstance.String()
+		}
+
+		var thrower, steamID, side string
+		if g.Thrower != nil {
+			thrower = g.Thrower.Name
+			steamID = playerSteamID(g.Thrower)
+			side = sideName(g.Thrower.Team)
+		}
+
+		tick := p.GameState().IngameTick()
+		pos := g.Position()
+		grenadesWriter.Write([]string{
+			strconv.Itoa(tick),
+			formatSeconds(gameTimeSeconds(tick, tickRate(p))),
+			strconv.Itoa(currentRound),
+			thrower,
+			steamID,
+			side,
+			grenadeType,
+			fmt.Sprintf("%.2f", pos.X),
+			fmt.Sprintf("%.2f", pos.Y),
+			fmt.Sprintf("%.2f", pos.Z),
+		})
+	})
+}
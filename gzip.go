@@ -0,0 +1,34 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+)
+
+var (
+	gzipOutput    bool
+	compressLevel int
+)
+
+// gzipFile compresses into an output and closes both on Close
+type gzipFile struct {
+	*gzip.Writer
+	file io.WriteCloser
+}
+
+func newGzipFile(file io.WriteCloser) (io.WriteCloser, error) {
+	zw, err := gzip.NewWriterLevel(file, compressLevel)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &gzipFile{Writer: zw, file: file}, nil
+}
+
+func (g *gzipFile) Close() error {
+	err := g.Writer.Close()
+	if closeErr := g.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	// idleTimeout is -idle-timeout in seconds
+	idleTimeout float64
+	// idleSince is the tick of the last activity once the match is over, -1
+	// while it's still going
+	idleSince   = -1
+	idleStopped bool
+)
+
+// registerIdleHandlers starts the idle clock when the match is won and
+// restarts it on any kill or round afterwards, so only the empty tail of a
+// demo is cut, never a pause or a long round during the match.
+func registerIdleHandlers(p dem.Parser) {
+	p.RegisterEventHandler(func(e events.AnnouncementWinPanelMatch) {
+		idleSince = p.GameState().IngameTick()
+	})
+	p.RegisterEventHandler(func(e events.MatchStart) {
+		idleSince = -1
+	})
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		if idleSince >= 0 {
+			idleSince = p.GameState().IngameTick()
+		}
+	})
+	p.RegisterEventHandler(func(e events.Kill) {
+		if idleSince >= 0 {
+			idleSince = p.GameState().IngameTick()
+		}
+	})
+}
+
+// idleExpired reports whether -idle-timeout has passed since the last
+// activity after the match ended
+func idleExpired(tick int, rate float64) bool {
+	return idleSince >= 0 && float64(tick-idleSince) > idleTimeout*rate
+}
+
+// reportIdleStop tells how much of the demo was left unparsed, estimated
+// from the frames the header says the demo has
+func reportIdleStop(p dem.Parser) {
+	h := p.Header()
+	note := "parsing stopped by -idle-timeout after the match ended"
+	if h.PlaybackFrames > 0 {
+		left := max(h.PlaybackFrames-p.CurrentFrame(), 0)
+		skipped := h.PlaybackTime.Seconds() * float64(left) / float64(h.PlaybackFrames)
+		note += fmt.Sprintf(", skipping about %.0fs (%d frames) of demo", skipped, left)
+	}
+	fmt.Printf("💤 Nothing happened for %.0fs, %s\n", idleTimeout, note)
+	metaNotes = append(metaNotes, note)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	exportInfernos bool
+	// infernoEvery is how many ticks apart burning infernos are sampled
+	infernoEvery    int
+	lastInfernoTick int
+	infernosFile    io.WriteCloser
+	infernosWriter  recordWriter
+)
+
+func registerInfernoHandlers(p dem.Parser) {
+	infernosFile, infernosWriter = openCSV(outputPath("infernos.csv"), []string{
+		"tick", "game_time_seconds", "round", "inferno_id", "thrower", "fire_count", "fires",
+	})
+
+	p.RegisterEventHandler(func(e events.InfernoStart) {
+		if e.Inferno == nil || skippingRound {
+			return
+		}
+		writeInferno(p.GameState().IngameTick(), tickRate(p), e.Inferno, e.Inferno.Fires().Active().List())
+	})
+
+	// A last row without fires marks when the inferno burnt out
+	p.RegisterEventHandler(func(e events.InfernoExpired) {
+		if e.Inferno == nil || skippingRound {
+			return
+		}
+		writeInferno(p.GameState().IngameTick(), tickRate(p), e.Inferno, nil)
+	})
+}
+
+// trackInfernos writes the burning fires of every inferno each -inferno-every
+// ticks, enough to follow the fire spreading and going out
+func trackInfernos(ctx tickContext, gs dem.GameState) {
+	if lastInfernoTick > 0 && ctx.tick-lastInfernoTick < infernoEvery {
+		return
+	}
+	lastInfernoTick = ctx.tick
+
+	infernos := gs.Infernos()
+	ids := make([]int, 0, len(infernos))
+	for id := range infernos {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		writeInferno(ctx.tick, ctx.rate, infernos[id], infernos[id].Fires().Active().List())
+	}
+}
+
+// writeInferno writes fires as "x y z" points separated by semicolons
+func writeInferno(tick int, rate float64, inf *common.Inferno, fires []common.Fire) {
+	points := make([]string, len(fires))
+	for i, f := range fires {
+		points[i] = fmt.Sprintf("%.1f %.1f %.1f", f.X, f.Y, f.Z)
+	}
+	var thrower string
+	if t := inf.Thrower(); t != nil {
+		thrower = t.Name
+	}
+	infernosWriter.Write([]string{
+		strconv.Itoa(tick),
+		formatSeconds(gameTimeSeconds(tick, rate)),
+		strconv.Itoa(currentRound),
+		strconv.FormatInt(inf.UniqueID(), 10),
+		thrower,
+		strconv.Itoa(len(fires)),
+		strings.Join(points, ";"),
+	})
+}
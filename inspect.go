@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+// runInspect parses a demo and prints its header and round results without
+// writing any output files.
+func runInspect(args []string) {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	demoPath := fs.String("demo", "protestdemo.dem", "Path to the demo file")
+	dumpHeader := fs.Bool("dump-header", false, "If true, only read the header, which is near-instant; demos can also be passed as arguments")
+	fs.Parse(args)
+
+	if *dumpHeader {
+		demos := fs.Args()
+		if len(demos) == 0 {
+			demos = []string{*demoPath}
+		}
+		for _, path := range demos {
+			printHeader(path)
+		}
+		return
+	}
+
+	f, err := os.Open(*demoPath)
+	if err != nil {
+		log.Fatal("❌ Failed to open demo:", err)
+	}
+	defer f.Close()
+
+	p := dem.NewParser(f)
+	defer p.Close()
+
+	type roundResult struct {
+		tick   int
+		winner common.Team
+	}
+	var rounds []roundResult
+
+	p.RegisterEventHandler(func(e events.RoundEnd) {
+		rounds = append(rounds, roundResult{tick: p.GameState().IngameTick(), winner: e.Winner})
+	})
+
+	err = p.ParseToEnd()
+	if err != nil {
+		log.Fatalf("❌ Error during parsing: %v", err)
+	}
+
+	h := p.Header()
+	fmt.Printf("Demo:        %s\n", *demoPath)
+	fmt.Printf("Map:         %s\n", h.MapName)
+	fmt.Printf("Server:      %s\n", h.ServerName)
+	fmt.Printf("Client:      %s\n", h.ClientName)
+	fmt.Printf("Tick rate:   %.0f\n", tickRate(p))
+	fmt.Printf("Duration:    %s (%d ticks, %d frames)\n", h.PlaybackTime, h.PlaybackTicks, h.PlaybackFrames)
+	fmt.Printf("Rounds:      %d\n", len(rounds))
+
+	for i, r := range rounds {
+		fmt.Printf("  round %-3d won by %-2s at tick %d\n", i+1, sideName(r.winner), r.tick)
+	}
+}
+
+// printHeader reads just the header of a demo and prints it. The tick rate
+// is derived from the playback totals since nothing has been parsed.
+func printHeader(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatal("❌ Failed to open demo:", err)
+	}
+	defer f.Close()
+
+	p := dem.NewParser(f)
+	defer p.Close()
+
+	h, err := p.ParseHeader()
+	if err != nil {
+		log.Fatalf("❌ Failed to read header of %s: %v", path, err)
+	}
+
+	rate := "unknown"
+	if h.PlaybackTime > 0 && h.PlaybackTicks > 0 {
+		rate = fmt.Sprintf("%.0f", float64(h.PlaybackTicks)/h.PlaybackTime.Seconds())
+	}
+	fmt.Printf("Demo:        %s\n", path)
+	fmt.Printf("Map:         %s\n", h.MapName)
+	fmt.Printf("Server:      %s\n", h.ServerName)
+	fmt.Printf("Tick rate:   %s\n", rate)
+	fmt.Printf("Duration:    %s (%d ticks, %d frames)\n", h.PlaybackTime, h.PlaybackTicks, h.PlaybackFrames)
+}
+
+// sideName returns the short side label used across the exported files.
+func sideName(team common.Team) string {
+	switch team {
+	case common.TeamCounterTerrorists:
+		return "CT"
+	case common.TeamTerrorists:
+		return "T"
+	case common.TeamSpectators:
+		return "SPEC"
+	}
+	return ""
+}
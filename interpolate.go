@@ -0,0 +1,57 @@
+package main
+
+import (
+	"math"
+
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+// interpolate makes -interval-ms rows hold the position and view at the
+// exact interval boundary, interpolated linearly between the ticks around
+// it. That assumes players move in a straight line for the tick or so in
+// between, which holds except for teleports, which are left as they are.
+var (
+	interpolate bool
+	prevPoses   = map[string]timedPose{}
+)
+
+type timedPose struct {
+	tick int
+	pose playerPose
+}
+
+// rememberPoses keeps every player's pose of the frame just processed, the
+// lower end of the next boundary's bracket
+func rememberPoses(players []*common.Player, tick int) {
+	for _, player := range players {
+		prevPoses[playerKey(player)] = timedPose{tick, poseOf(player)}
+	}
+}
+
+// interpolatedPose moves pose, taken on ctx.tick, back to the boundary
+// onInterval last passed
+func interpolatedPose(ctx tickContext, player *common.Player, pose playerPose) playerPose {
+	prev, ok := prevPoses[playerKey(player)]
+	if !ok || prev.tick >= ctx.tick {
+		return pose
+	}
+	dt := float64(ctx.tick - prev.tick)
+	if prev.pose.pos.Distance(pose.pos) > teleportSpeed*dt/ctx.rate {
+		return pose
+	}
+
+	f := math.Max(0, math.Min(1, (intervalBoundary-float64(prev.tick))/dt))
+	return playerPose{
+		pos:   prev.pose.pos.Add(pose.pos.Sub(prev.pose.pos).Mul(f)),
+		viewX: lerpYaw(prev.pose.viewX, pose.viewX, f),
+		viewY: prev.pose.viewY + (pose.viewY-prev.pose.viewY)*float32(f),
+	}
+}
+
+// lerpYaw interpolates along the shorter way around the circle, so 359° to
+// 1° passes 0° rather than 180°
+func lerpYaw(from, to float32, f float64) float32 {
+	diff := math.Mod(float64(to-from)+540, 360) - 180
+	yaw := math.Mod(float64(from)+diff*f+360, 360)
+	return float32(yaw)
+}
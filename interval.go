@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+)
+
+var (
+	// intervalMs samples the tick export every N milliseconds of game time
+	// instead of every tick, so demos of different tick rates line up
+	intervalMs int
+	// targetTickRate resamples the tick export to that many rows per second
+	// of game time, which is -interval-ms with a fractional interval
+	targetTickRate float64
+	// intervalSeconds is the sampling interval either flag asked for, 0 when
+	// every tick is exported
+	intervalSeconds float64
+	// nextInterval is the index of the next interval boundary to sample
+	nextInterval int
+	// intervalBoundary is the exact, fractional tick of the boundary the
+	// current sample stands for
+	intervalBoundary float64
+)
+
+// intervalTick returns the tick closest to interval boundary k, or with
+// -interpolate the first tick at or after it, so the ticks bracket it
+func intervalTick(k int, rate float64) int {
+	exact := intervalExact(k, rate)
+	if interpolate {
+		return int(math.Ceil(exact))
+	}
+	return int(math.Round(exact))
+}
+
+func intervalExact(k int, rate float64) float64 {
+	return float64(k) * intervalSeconds * rate
+}
+
+// onInterval reports whether tick should be sampled under -interval-ms. A
+// boundary whose closest tick isn't in the demo is sampled on the next tick
+// that is. Boundaries count from the start of the demo, not of each round.
+func onInterval(tick int, rate float64) bool {
+	if tick < intervalTick(nextInterval, rate) {
+		return false
+	}
+	for intervalTick(nextInterval, rate) <= tick {
+		intervalBoundary = intervalExact(nextInterval, rate)
+		nextInterval++
+	}
+	return true
+}
+
+// ticksPerSample is how many ticks a sampled row stands for
+func ticksPerSample(rate float64) float64 {
+	if intervalSeconds <= 0 {
+		return 1
+	}
+	return intervalSeconds * rate
+}
+
+// reportTargetTickRate warns when -target-tickrate is above the demo's own
+// rate. Ticks can't be made up, so such demos get one row per tick and
+// stay at their own rate.
+func reportTargetTickRate(rate float64) {
+	if targetTickRate <= rate {
+		return
+	}
+	note := fmt.Sprintf("-target-tickrate %g is above the demo's %g ticks/s, rows were written for every tick instead", targetTickRate, rate)
+	log.Printf("⚠️  %s", note)
+	metaNotes = append(metaNotes, note)
+}
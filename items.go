@@ -0,0 +1,50 @@
+package main
+
+import (
+	"io"
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	itemsFile   io.WriteCloser
+	itemsWriter recordWriter
+)
+
+func registerItemHandlers(p dem.Parser) {
+	itemsFile, itemsWriter = openCSV(outputPath("items.csv"), []string{
+		"tick", "game_time_seconds", "round", "player", "item", "action",
+	})
+
+	p.RegisterEventHandler(func(e events.ItemPickup) {
+		writeItemEvent(p, e.Player, e.Weapon, "pickup")
+	})
+
+	p.RegisterEventHandler(func(e events.ItemDrop) {
+		writeItemEvent(p, e.Player, e.Weapon, "drop")
+	})
+}
+
+func writeItemEvent(p dem.Parser, player *common.Player, item *common.Equipment, action string) {
+	if skippingRound {
+		return
+	}
+
+	itemName := ""
+	if item != nil {
+		itemName = item.String()
+	}
+
+	tick := p.GameState().IngameTick()
+	itemsWriter.Write([]string{
+		strconv.Itoa(tick),
+		formatSeconds(gameTimeSeconds(tick, tickRate(p))),
+		strconv.Itoa(currentRound),
+		playerName(player), // world drops (e.g. the bomb spawning on the ground) have no player
+		itemName,
+		action,
+	})
+}
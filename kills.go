@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+// damageHit is one PlayerHurt event remembered for assist attribution
+type damageHit struct {
+	attacker uint64
+	tick     int
+	damage   int
+}
+
+var (
+	exportKills  bool
+	assistWindow float64
+	killsFile    io.WriteCloser
+	killsWriter  recordWriter
+	recentDamage = map[uint64][]damageHit{}
+)
+
+func registerKillHandlers(p dem.Parser) {
+	killsFile, killsWriter = openCSV(outputPath("kills.csv"), []string{
+		"tick", "game_time_seconds", "round", "round_time_seconds",
+		"killer", "killer_steamid", "victim", "victim_steamid",
+		"weapon", "headshot", "damage_by",
+		"wallbang", "through_smoke", "penetration_count",
+	})
+
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		clear(recentDamage)
+	})
+
+	p.RegisterEventHandler(func(e events.PlayerHurt) {
+		if e.Player == nil || e.Attacker == nil {
+			return
+		}
+		victim := e.Player.SteamID64
+		recentDamage[victim] = append(recentDamage[victim], damageHit{
+			attacker: e.Attacker.SteamID64,
+			tick:     p.GameState().IngameTick(),
+			damage:   e.HealthDamageTaken,
+		})
+	})
+
+	p.RegisterEventHandler(func(e events.Kill) {
+		if skippingRound {
+			return
+		}
+		tick := p.GameState().IngameTick()
+
+		damageBy := ""
+		if e.Victim != nil {
+			damageBy = encodeDamageBy(recentDamage[e.Victim.SteamID64], tick-int(assistWindow*tickRate(p)))
+			delete(recentDamage, e.Victim.SteamID64)
+		}
+		if teamDamageOnly && !isTeamDamage(e.Killer, e.Victim) {
+			return
+		}
+
+		weapon := ""
+		if e.Weapon != nil {
+			weapon = e.Weapon.String()
+		}
+
+		killsWriter.Write([]string{
+			strconv.Itoa(tick),
+			formatSeconds(gameTimeSeconds(tick, tickRate(p))),
+			strconv.Itoa(currentRound),
+			roundTimeSeconds(tick, tickRate(p)),
+			playerName(e.Killer),
+			playerSteamID(e.Killer),
+			playerName(e.Victim),
+			playerSteamID(e.Victim),
+			weapon,
+			boolToIntString(e.IsHeadshot),
+			damageBy,
+			// Always 0 rather than empty when the bullet went straight through
+			boolToIntString(e.PenetratedObjects > 0),
+			boolToIntString(e.ThroughSmoke),
+			strconv.Itoa(e.PenetratedObjects),
+		})
+	})
+}
+
+// encodeDamageBy sums the damage each attacker dealt since fromTick and
+// encodes it as "steamid:damage;steamid:damage", in order of first hit.
+func encodeDamageBy(hits []damageHit, fromTick int) string {
+	var order []uint64
+	totals := map[uint64]int{}
+	for _, h := range hits {
+		if h.tick < fromTick {
+			continue
+		}
+		if _, seen := totals[h.attacker]; !seen {
+			order = append(order, h.attacker)
+		}
+		totals[h.attacker] += h.damage
+	}
+
+	parts := make([]string, 0, len(order))
+	for _, id := range order {
+		parts = append(parts, fmt.Sprintf("%d:%d", id, totals[id]))
+	}
+	return strings.Join(parts, ";")
+}
+
+func playerName(player *common.Player) string {
+	if player == nil {
+		return ""
+	}
+	return player.Name
+}
+
+func playerSteamID(player *common.Player) string {
+	if player == nil {
+		return ""
+	}
+	return strconv.FormatUint(player.SteamID64, 10)
+}
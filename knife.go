@@ -0,0 +1,28 @@
+package main
+
+import (
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+var (
+	skipKnifeRound    bool
+	knifeRoundChecked bool
+
+	// skippingRound is set while the current round is excluded from all outputs
+	skippingRound bool
+)
+
+// isKnifeRound reports whether every player spawned with nothing but a knife
+// (and possibly the bomb).
+func isKnifeRound(gs dem.GameState) bool {
+	players := gs.Participants().Playing()
+	for _, player := range players {
+		for _, w := range player.Weapons() {
+			if w.Type != common.EqKnife && w.Type != common.EqBomb {
+				return false
+			}
+		}
+	}
+	return len(players) > 0
+}
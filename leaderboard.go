@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+// playerTotals are a player's stats summed over every demo of the batch, or
+// over one demo for player_stats.csv
+type playerTotals struct {
+	steamID       uint64
+	name          string
+	kills, deaths int
+	damage        int
+	rounds        int
+}
+
+var (
+	exportLeaderboard bool
+	leaderboard       = map[uint64]*playerTotals{}
+	// demoStats are the current demo's totals for -summary-only
+	demoStats = map[uint64]*playerTotals{}
+)
+
+// statsEntries returns the totals to update for player: its leaderboard
+// entry and, with -summary-only, its entry for this demo. Bots all share
+// SteamID 0 and can't be told apart across demos, so they get none.
+func statsEntries(player *common.Player) []*playerTotals {
+	if player == nil || player.SteamID64 == 0 {
+		return nil
+	}
+	var entries []*playerTotals
+	if exportLeaderboard {
+		entries = append(entries, totalsEntry(leaderboard, player))
+	}
+	if summaryOnly {
+		entries = append(entries, totalsEntry(demoStats, player))
+	}
+	return entries
+}
+
+func totalsEntry(totals map[uint64]*playerTotals, player *common.Player) *playerTotals {
+	t, ok := totals[player.SteamID64]
+	if !ok {
+		t = &playerTotals{steamID: player.SteamID64}
+		totals[player.SteamID64] = t
+	}
+	t.name = player.Name
+	return t
+}
+
+func registerLeaderboardHandlers(p dem.Parser) {
+	p.RegisterEventHandler(func(e events.Kill) {
+		// Warmup frags would inflate the totals
+		if skippingRound || currentRound == 0 || p.GameState().IsWarmupPeriod() || e.Victim == nil {
+			return
+		}
+		for _, victim := range statsEntries(e.Victim) {
+			victim.deaths++
+		}
+		// Suicides and team kills don't count
+		if e.Killer != nil && e.Killer != e.Victim && e.Killer.Team != e.Victim.Team {
+			for _, killer := range statsEntries(e.Killer) {
+				killer.kills++
+			}
+		}
+	})
+
+	p.RegisterEventHandler(func(e events.PlayerHurt) {
+		if skippingRound || currentRound == 0 || p.GameState().IsWarmupPeriod() {
+			return
+		}
+		if e.Player == nil || e.Attacker == nil || e.Attacker.Team == e.Player.Team {
+			return
+		}
+		for _, attacker := range statsEntries(e.Attacker) {
+			attacker.damage += e.HealthDamageTaken
+		}
+	})
+
+	// Only real rounds count towards rounds played, or ADR would be diluted
+	p.RegisterEventHandler(func(e events.RoundEnd) {
+		if skippingRound || currentRound == 0 || p.GameState().IsWarmupPeriod() {
+			return
+		}
+		for _, player := range p.GameState().Participants().Playing() {
+			for _, t := range statsEntries(player) {
+				t.rounds++
+			}
+		}
+	})
+}
+
+func writeLeaderboard(path string) {
+	n := writePlayerTotals(leaderboard, path)
+	fmt.Printf("🏆 Leaderboard for %d players written to %s\n", n, path)
+}
+
+// writePlayerTotals writes byPlayer sorted by kills and returns the number
+// of players
+func writePlayerTotals(byPlayer map[uint64]*playerTotals, path string) int {
+	totals := make([]*playerTotals, 0, len(byPlayer))
+	for _, t := range byPlayer {
+		totals = append(totals, t)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].kills != totals[j].kills {
+			return totals[i].kills > totals[j].kills
+		}
+		return totals[i].steamID < totals[j].steamID
+	})
+
+	file, writer := createCSV(path, []string{"steamid", "player_name", "kills", "deaths", "adr", "rounds_played"})
+
+	for _, t := range totals {
+		adr := 0.0
+		if t.rounds > 0 {
+			adr = float64(t.damage) / float64(t.rounds)
+		}
+		writer.Write([]string{
+			strconv.FormatUint(t.steamID, 10),
+			t.name,
+			strconv.Itoa(t.kills),
+			strconv.Itoa(t.deaths),
+			fmt.Sprintf("%.1f", adr),
+			strconv.Itoa(t.rounds),
+		})
+	}
+	closeOutput(file, writer)
+	return len(totals)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	limitRounds int
+	// roundsEnded counts real rounds, leaving out warmup and a skipped
+	// knife round
+	roundsEnded  int
+	limitReached bool
+)
+
+// registerRoundLimitHandlers cancels parsing at the RoundEnd of the
+// -limit-rounds'th real round. It has to be registered after every other
+// RoundEnd handler so rounds.csv, the scoreboard, ... still see that round.
+func registerRoundLimitHandlers(p dem.Parser) {
+	p.RegisterEventHandler(func(e events.RoundEnd) {
+		if limitReached || skippingRound || currentRound == 0 || p.GameState().IsWarmupPeriod() {
+			return
+		}
+		roundsEnded++
+		if roundsEnded >= limitRounds {
+			limitReached = true
+			p.Cancel()
+		}
+	})
+}
+
+// reportRoundLimit tells how many rounds were parsed, also when the demo ran
+// out before -limit-rounds was reached
+func reportRoundLimit() {
+	if limitReached {
+		note := fmt.Sprintf("parsing stopped by -limit-rounds after %d rounds", roundsEnded)
+		fmt.Printf("⏹️  %s\n", note)
+		metaNotes = append(metaNotes, note)
+		return
+	}
+	fmt.Printf("⏹️  The demo ended after %d of the %d rounds asked for with -limit-rounds\n", roundsEnded, limitRounds)
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"compress/gzip"
 	"encoding/csv"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -16,132 +19,1095 @@ import (
 )
 
 var (
-	currentRound  = 1
-	currentFile   *os.File
-	currentWriter *csv.Writer
+	currentRound  int
+	currentFile   io.WriteCloser
+	currentWriter recordWriter
 	lastTick      int
+	flushEvery    int
+	lastFlushTick int
 	outputFolder  string
 	splitRounds   bool
-	baseWriter    *csv.Writer
-	baseFile      *os.File
+	outputFormat  string
+	exportItems   bool
+	exportRounds  bool
+	baseWriter    recordWriter
+	baseFile      io.WriteCloser
 )
 
+// version is overridden at build time via -ldflags "-X main.version=..."
+var version = "dev"
+
 func main() {
+	// Pick the subcommand; bare flags keep the old behaviour of exporting
+	cmd, args := "export", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+
+	switch cmd {
+	case "export":
+		runExport(args)
+	case "inspect":
+		runInspect(args)
+	case "convert":
+		runConvert(args)
+	case "version":
+		fmt.Printf("democamexporter %s (tick schema %d)\n", version, tickSchemaVersion)
+	default:
+		log.Fatalf("❌ Unknown command %q (expected export, inspect, convert or version)", cmd)
+	}
+}
+
+func runExport(args []string) {
 	// Command-line flags
-	demoPath := flag.String("demo", "protestdemo.dem", "Path to the demo file")
-	flag.BoolVar(&splitRounds, "split-rounds", false, "If true, split output per round into separate CSV files")
-	flag.Parse()
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	demoPath := fs.String("demo", "protestdemo.dem", "Path to the demo file (or a folder of demos); demos can also be passed as arguments")
+	demoParts := fs.String("demo-parts", "", "Comma separated, ordered parts of one split demo to export as a single demo (overrides -demo)")
+	outDir := fs.String("out-dir", ".", "Directory (or s3://bucket/prefix, gs://bucket/prefix) to write the output folder into")
+	fs.BoolVar(&splitRounds, "split-rounds", false, "If true, split output per round into separate CSV files")
+	fs.BoolVar(&fsyncRounds, "fsync-rounds", false, "If true, fsync every -split-rounds file (and -split-players file) when its round is done, so completed rounds survive a crash (slower)")
+	fs.BoolVar(&splitPlayers, "split-players", false, "If true, write one tick file per player (player_<steamid>); combined with -split-rounds they are nested in round_<n> folders")
+	fs.StringVar(&tickFilename, "tick-filename", "all_ticks{ext}", "Name of the single tick file; supports {demo}, {map}, {tickrate} and {ext}")
+	fs.StringVar(&roundFilename, "round-filename", "round_{round}{ext}", "Name of the per-round tick files; supports {demo}, {map}, {tickrate}, {round} and {ext}")
+	tickRateInName := fs.Bool("tick-rate-in-filename", false, "If true, add the map and detected tick rate to the tick file names (e.g. all_ticks_de_mirage_64.csv)")
+	maxSizeFlag := fs.String("max-file-size", "", "Continue the single tick file in all_ticks.001.csv, .002.csv, ... whenever it would grow past this size, e.g. 2G or 500M")
+	fs.StringVar(&protoInt, "proto-int", "int32", "Width of the integer TickRecord fields for -format protobuf: int32 (as in tick.proto, values are range checked) or int64")
+	fs.StringVar(&protoFloat, "proto-float", "float32", "Width of the float TickRecord fields for -format protobuf: float32 (as in tick.proto) or float64 (compile tick.proto with double)")
+	fs.StringVar(&nullToken, "null-token", "", "Written instead of empty CSV fields for missing data, e.g. \\N or NULL for database bulk loaders")
+	fs.BoolVar(&gzipOutput, "gzip", false, "If true, gzip every output file (adding .gz to its name)")
+	fs.IntVar(&compressLevel, "compress-level", gzip.DefaultCompression, "Gzip level for -gzip, from 0 (none) and 1 (fastest) to 9 (smallest); -1 is the standard level")
+	fs.StringVar(&outputFormat, "format", "csv", "Tick file format: csv or protobuf (length-delimited TickRecord, see proto/tick.proto)")
+	fs.BoolVar(&emptyRounds, "emit-empty-rounds", false, "If true, -split-rounds still writes a header-only file for rounds left out by -round-outcome")
+	fs.BoolVar(&exportItems, "items", false, "If true, also write item pickups and drops to items.csv")
+	fs.BoolVar(&exportMovement, "movement-events", false, "If true, also write crouch and jump transitions to movement_events.csv")
+	fs.BoolVar(&exportKills, "kills", false, "If true, also write kills to kills.csv")
+	fs.Float64Var(&assistWindow, "assist-window", 5, "Seconds of damage before a kill credited in the kills.csv damage_by column")
+	fs.BoolVar(&exportDamage, "damage", false, "If true, also write every damage event to damage.csv")
+	fs.BoolVar(&teamDamageOnly, "team-damage-only", false, "If true, damage.csv and kills.csv only contain friendly fire")
+	fs.BoolVar(&exportGrenades, "grenades", false, "If true, also write every grenade throw, with its exact type (Molotov and Incendiary apart) and the thrower's side, to grenades.csv")
+	fs.BoolVar(&exportOpenings, "opening-kills", false, "If true, also write the first kill of every round to opening_kills.csv")
+	fs.BoolVar(&exportZoom, "zoom-events", false, "If true, also write scope in/out transitions to zoom_events.csv")
+	fs.BoolVar(&textLog, "text-log", false, "If true, also write a readable log of rounds, kills and bomb events to events.log")
+	fs.BoolVar(&exportUtility, "utility-damage", false, "If true, also write HE and fire damage dealt to enemies per player and round to utility_damage.csv")
+	fs.BoolVar(&exportActivity, "activity-summary", false, "If true, also write each player's share of round time spent moving, crouched, scoped, ... and the seconds spent scoped to activity_summary.csv")
+	fs.BoolVar(&exportEconomy, "economy", false, "If true, also write every change of a player's money, with its likely reason, to economy.csv")
+	fs.BoolVar(&exportInfernos, "infernos", false, "If true, also write the burning fire points of every molotov/incendiary over time to infernos.csv")
+	fs.IntVar(&infernoEvery, "inferno-every", 16, "Ticks between the samples of burning infernos for -infernos")
+	fs.BoolVar(&exportSpotted, "spotted", false, "If true, also write who has spotted whom on every tick, as tracked by the game, to spotted.csv")
+	fs.BoolVar(&exportTeamPositions, "team-aggregate-positions", false, "If true, also write each side's centroid and spread of living players for every tick to team_positions.csv")
+	fs.BoolVar(&exportVisibility, "visibility", false, "If true, also write for every tick which living enemies each player has in view to visibility.csv (approximate: FOV and distance only, walls are ignored)")
+	fs.Float64Var(&visibilityFOV, "visibility-fov", 106, "Horizontal field of view in degrees for -visibility")
+	fs.Float64Var(&visibilityMaxDist, "visibility-max-dist", 0, "If > 0, enemies further away than this many units don't count as visible for -visibility")
+	fs.BoolVar(&exportReactions, "reactions", false, "If true, also write the ticks from first contact with an enemy to the player's next shot to reactions.csv")
+	fs.StringVar(&reactionContact, "reaction-contact", "fov", "What counts as first contact for -reactions: fov (in -visibility-fov, walls ignored), spotted (the game's spotted state) or both")
+	fs.Float64Var(&reactionWindow, "reaction-window-ms", 1500, "Shots later than this after first contact don't count as a reaction for -reactions")
+	fs.BoolVar(&exportBomb, "bomb", false, "If true, also write the C4 position and state (carried, dropped, planted, ...) for every tick to bomb_pos.csv")
+	fs.BoolVar(&exportRounds, "rounds-csv", false, "If true, also write one summary row per round to rounds.csv")
+	fs.BoolVar(&exportPhases, "phase-events", false, "If true, also write every round phase change (round_start, freezetime_end, bomb_planted, round_end) to phases.csv")
+	fs.BoolVar(&exportScoreboard, "scoreboard", false, "If true, also write every player's scoreboard stats at each round end to scoreboard.csv")
+	fs.BoolVar(&summaryOnly, "summary-only", false, "If true, skip the tick files and event exports and only write meta.json, rounds.csv and per-player totals to player_stats.csv")
+	allEvents := fs.Bool("all-events", false, "If true, turn on every event exporter (-kills, -damage, -bomb, -rounds-csv, -spotted, -reactions, ...); leave single ones out with e.g. -kills=false")
+	calloutsFlag := fs.Bool("callouts", false, "If true, add a location column naming each player's map area (A Site, Mid, ...)")
+	calloutPath := fs.String("callout-regions", "", "JSON file of named regions per map to use for -callouts instead of the game's place names")
+	steamIDsPath := fs.String("expected-steamids", "", "File with one allowed SteamID64 per line; players not in it are reported at the end of each demo")
+	fs.BoolVar(&failOnUnexpected, "fail-on-unexpected-steamids", false, "If true, exit with an error after the run when -expected-steamids found players not in the list")
+	objectivesFlag := fs.Bool("objective-distances", false, "If true, add dist_to_bomb, dist_to_a and dist_to_b columns")
+	siteCentersPath := fs.String("site-centers", "", "JSON file of bomb site centers per map for -objective-distances, e.g. {\"de_mirage\": {\"a\": [x, y, z], \"b\": [x, y, z]}}; other maps learn them from where players stand on the sites")
+	fs.BoolVar(&pseudonyms, "pseudonyms", false, "If true, replace player names and SteamIDs in the tick export with random pseudonyms, new for every demo")
+	pseudonymMapPath := fs.String("pseudonym-map", "", "Where -pseudonyms writes which pseudonym stands for whom; keep it out of the shared dataset")
+	pseudonymSeed := fs.Int64("pseudonym-seed", 0, "If not 0, seed -pseudonyms so the same demos in the same order get the same pseudonyms")
+	rosterPath := fs.String("roster", "", "CSV with a steamid column whose other columns (e.g. real_name, role) are added to the tick export")
+	fs.BoolVar(&roundsJSON, "round-summary-json", false, "If true, also write the per-round summary as a JSON array to rounds.json")
+	fs.StringVar(&dedupBy, "dedup-by", "tick", "Which frames get rows: tick (first frame of each tick), frame (every sub-tick frame in which a player moved or turned; the frame column holds the frame id) or none (every FrameDone)")
+	ignoreDuplicateTicks := fs.Bool("ignore-duplicate-ticks", false, "Same as -dedup-by none (kept for old scripts)")
+	fs.BoolVar(&positionsOnly, "positions-only", false, "If true, tick files only have tick, steamid and position columns, for faster exports")
+	fs.BoolVar(&angleOnly, "angle-only", false, "If true, tick files only have tick, steamid, yaw, pitch and the angle between the view and the nearest enemy")
+	fs.IntVar(&intervalMs, "interval-ms", 0, "If > 0, sample the tick export every N ms of game time (closest tick to each boundary) instead of every tick")
+	fs.Float64Var(&targetTickRate, "target-tickrate", 0, "If > 0, resample the tick export to this many rows per second of game time, e.g. 64 for 128-tick demos (same as -interval-ms with a fractional interval, so the two can't be combined; add -interpolate for exact positions)")
+	fs.BoolVar(&interpolate, "interpolate", false, "If true, -interval-ms rows hold positions and views interpolated to the exact interval boundary instead of the nearest tick's")
+	fs.Float64Var(&aimThreshold, "aim-threshold-deg", 5, "Degrees the view may be off a living enemy for aiming_at_enemy (walls are not taken into account)")
+	fs.IntVar(&flushEvery, "flush-every", 0, "If > 0, flush all output files every N ticks (default: only when they are closed)")
+	fs.IntVar(&skipFirstFrames, "skip-first-frames", 0, "If > 0, write no tick rows for the first N sampled frames of the demo, while entities settle")
+	fs.BoolVar(&postPlantOnly, "post-plant-only", false, "If true, only write ticks from the bomb plant to the end of each round, with a time_since_plant column")
+	fs.IntVar(&roundStartDelay, "round-start-delay-ticks", 0, "If > 0, start writing each round's ticks N ticks after its start, once players have settled at their spawns")
+	fs.BoolVar(&skipPerRound, "skip-per-round", false, "If true, -skip-first-frames also applies after every round start")
+	fs.BoolVar(&strict, "strict", false, "If true, skip rows whose player position or entity data is missing")
+	fs.BoolVar(&skipKnifeRound, "skip-knife-round", false, "If true, leave the knife round out of all outputs and start counting rounds after it")
+	fs.BoolVar(&positionDeltas, "deltas", false, "If true, write dx/dy/dz movement since the previous sampled tick instead of absolute positions (the first row of a player in each round is absolute)")
+	fs.BoolVar(&unwrapView, "unwrap-yaw", false, "If true, unwrap view_dir_x per player so it changes continuously instead of jumping at ±180°")
+	fs.Float64Var(&teleportSpeed, "teleport-speed", 3500, "Movement in units per second above which a row is flagged in the teleport column")
+	fs.BoolVar(&compactNames, "compact-names", false, "If true, write a small player_id instead of player_name in tick rows, with the mapping in name_map.csv")
+	fs.BoolVar(&carryDead, "carry-dead", false, "If true, dead players keep their last alive position and view until the round ends")
+	fs.IntVar(&windowTicks, "round-window", 0, "If > 0, write all players' positions for N ticks around each kill to kill_windows.csv")
+	fs.BoolVar(&exportLeaderboard, "leaderboard", false, "If true, write per-player totals across all exported demos to leaderboard.csv in -out-dir")
+	boundsFlag := fs.String("clamp-bounds", "", "World bounds as minx,miny,minz,maxx,maxy,maxz; rows outside them are handled per -bounds-mode")
+	fs.BoolVar(&normalizePositions, "normalize-positions", false, "If true, rescale positions to [0,1] within the map's bounds (built in for the competitive maps)")
+	normalizeFlag := fs.String("normalize-bounds", "", "Bounds for -normalize-positions as minx,miny,minz,maxx,maxy,maxz, for maps without built-in bounds")
+	fs.StringVar(&boundsMode, "bounds-mode", "skip", "What to do with rows outside -clamp-bounds: skip or clamp")
+	cpuProfile := fs.String("cpuprofile", "", "Write a pprof CPU profile of the run to this file")
+	memProfile := fs.String("memprofile", "", "Write a pprof heap profile to this file at the end of the run")
+	fs.BoolVar(&keepPastMatchEnd, "keep-going-past-match-end", false, "If true, keep writing after the match is won instead of leaving out the outro frames")
+	fs.IntVar(&limitRounds, "limit-rounds", 0, "If > 0, stop parsing after this many real rounds (warmup and a skipped knife round don't count)")
+	fs.Float64Var(&idleTimeout, "idle-timeout", 0, "If > 0, stop parsing once nothing has happened for this many seconds after the match ended (trims idle tails of POV demos)")
+	fs.BoolVar(&throttleMemory, "throttle-memory", false, "If true, keep memory use down (about 512 MiB) for constrained workers: parse synchronously, flush often and drop state of players who left")
+	fs.IntVar(&msgQueueSize, "msg-queue-size", -1, "Parser message queue size; -1 picks a size from the demo header, 0 parses synchronously")
+	fs.BoolVar(&noSource1Events, "no-source1-events", false, "If true, don't re-create CS:GO style events missing from CS2 demos (faster, but see README for the exporters affected)")
+	checkpointPath := fs.String("checkpoint", "", "File recording completed demos (by hash and path); demos already in it are skipped")
+	roundOutcome := fs.String("round-outcome", "", "Comma separated round outcomes (e.g. bomb_defused,target_saved); only rounds ending that way are written")
+	fs.Parse(args)
+	if *tickRateInName {
+		tickFilename = withTickRate(tickFilename)
+		roundFilename = withTickRate(roundFilename)
+	}
+	if *allEvents {
+		applyAllEvents(fs)
+	}
+	if throttleMemory {
+		given := givenFlags(fs)
+		applyThrottleMemory(given["msg-queue-size"], given["flush-every"])
+	}
+	if summaryOnly {
+		if err := checkSummaryOnly(fs); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+	}
+	if pseudonyms {
+		if err := setupPseudonyms(fs, *pseudonymMapPath, *pseudonymSeed); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		defer closeOutput(pseudonymFile, pseudonymMap)
+	}
 
-	// Prepare output folder name (based on demo file, without extension)
-	baseName := strings.TrimSuffix(filepath.Base(*demoPath), filepath.Ext(*demoPath))
-	outputFolder = baseName
+	var err error
+	if *ignoreDuplicateTicks {
+		dedupBy = "none"
+	}
+	switch {
+	case targetTickRate < 0:
+		log.Fatalf("❌ -target-tickrate must be positive")
+	case targetTickRate > 0 && intervalMs > 0:
+		log.Fatalf("❌ -target-tickrate and -interval-ms both set the sampling rate, use only one")
+	case targetTickRate > 0:
+		intervalSeconds = 1 / targetTickRate
+	default:
+		intervalSeconds = float64(intervalMs) / 1000
+	}
+	if interpolate && intervalSeconds <= 0 {
+		log.Fatalf("❌ -interpolate needs -interval-ms or -target-tickrate")
+	}
+	if dedupBy != "none" && dedupBy != "tick" && dedupBy != "frame" {
+		log.Fatalf("❌ Unknown -dedup-by %q (expected none, tick or frame)", dedupBy)
+	}
+	if reactionContact != "fov" && reactionContact != "spotted" && reactionContact != "both" {
+		log.Fatalf("❌ Unknown -reaction-contact %q (expected fov, spotted or both)", reactionContact)
+	}
+	if compressLevel < gzip.DefaultCompression || compressLevel > gzip.BestCompression {
+		log.Fatalf("❌ Invalid -compress-level %d (expected 0-9, or -1 for the standard level)", compressLevel)
+	}
+	if outputFormat != "csv" && outputFormat != "protobuf" {
+		log.Fatalf("❌ Unknown format %q (expected csv or protobuf)", outputFormat)
+	}
+	if protoInt != "int32" && protoInt != "int64" {
+		log.Fatalf("❌ Unknown -proto-int %q (expected int32 or int64)", protoInt)
+	}
+	if protoFloat != "float32" && protoFloat != "float64" {
+		log.Fatalf("❌ Unknown -proto-float %q (expected float32 or float64)", protoFloat)
+	}
+	if *roundOutcome != "" {
+		roundOutcomes, err = parseRoundOutcomes(*roundOutcome)
+		if err != nil {
+			log.Fatalf("❌ Invalid -round-outcome: %v", err)
+		}
+	}
+	if positionsOnly || angleOnly {
+		mode := pick(positionsOnly, "-positions-only", "-angle-only")
+		if positionsOnly && angleOnly {
+			log.Fatalf("❌ -positions-only and -angle-only can't be combined")
+		}
+		if positionDeltas || compactNames || unwrapView || carryDead || *boundsFlag != "" || normalizePositions {
+			log.Fatalf("❌ %s can't be combined with -deltas, -compact-names, -unwrap-yaw, -carry-dead, -clamp-bounds or -normalize-positions", mode)
+		}
+		tickHeader = positionsHeader
+		if angleOnly {
+			tickHeader = angleHeader
+		}
+	}
+	if positionDeltas {
+		tickHeader = deltaHeader(tickHeader)
+	}
+	if compactNames {
+		tickHeader = compactHeader(tickHeader)
+	}
+	if *rosterPath != "" {
+		roster, err := loadRoster(*rosterPath)
+		if err != nil {
+			log.Fatalf("❌ Failed to load -roster: %v", err)
+		}
+		RegisterColumnProvider(roster)
+	}
+	if *steamIDsPath != "" {
+		expectedSteamIDs, err = loadExpectedSteamIDs(*steamIDsPath)
+		if err != nil {
+			log.Fatalf("❌ Failed to load -expected-steamids: %v", err)
+		}
+	}
+	if postPlantOnly {
+		RegisterColumnProvider(postPlantColumns{})
+	}
+	if *objectivesFlag {
+		objectives = &objectiveColumns{}
+		if *siteCentersPath != "" {
+			objectives.fixedCenters, err = loadSiteCenters(*siteCentersPath)
+			if err != nil {
+				log.Fatalf("❌ Failed to load -site-centers: %v", err)
+			}
+		}
+		RegisterColumnProvider(objectives)
+	}
+	if *calloutsFlag {
+		callouts = &calloutColumns{}
+		if *calloutPath != "" {
+			callouts.regions, err = loadCalloutRegions(*calloutPath)
+			if err != nil {
+				log.Fatalf("❌ Failed to load -callout-regions: %v", err)
+			}
+		}
+		RegisterColumnProvider(callouts)
+	}
+	tickHeader = composeHeader(tickHeader)
+	if *boundsFlag != "" {
+		clampBounds, err = parseBounds(*boundsFlag)
+		if err != nil {
+			log.Fatalf("❌ Invalid -clamp-bounds: %v", err)
+		}
+	}
+	if *normalizeFlag != "" {
+		normalizeOverride, err = parseBounds(*normalizeFlag)
+		if err != nil {
+			log.Fatalf("❌ Invalid -normalize-bounds: %v", err)
+		}
+	}
+	if *maxSizeFlag != "" {
+		maxFileSize, err = parseSize(*maxSizeFlag)
+		if err != nil {
+			log.Fatalf("❌ Invalid -max-file-size: %v", err)
+		}
+	}
+	if boundsMode != "skip" && boundsMode != "clamp" {
+		log.Fatalf("❌ Unknown -bounds-mode %q (expected skip or clamp)", boundsMode)
+	}
+	if splitRounds && !strings.Contains(roundFilename, "{round}") {
+		log.Fatalf("❌ -round-filename must contain {round} so rounds don't overwrite each other")
+	}
 
-	err := os.MkdirAll(outputFolder, os.ModePerm)
-	if err != nil {
-		log.Fatalf("❌ Failed to create output folder: %v", err)
+	stopProfiling := startProfiling(*cpuProfile, *memProfile)
+	defer stopProfiling()
+	// log.Fatalf skips deferred calls, so failing runs would lose the profiles
+	fatalf := func(format string, args ...any) {
+		stopProfiling()
+		log.Fatalf(format, args...)
+	}
+
+	var demos [][]string
+	if *demoParts != "" {
+		demos = [][]string{strings.Split(*demoParts, ",")}
+	} else {
+		paths, err := collectDemos(*demoPath, fs.Args())
+		if err != nil {
+			fatalf("❌ Failed to find demos: %v", err)
+		}
+		for _, path := range paths {
+			demos = append(demos, []string{path})
+		}
+	}
+
+	var completed map[string]bool
+	if *checkpointPath != "" {
+		completed, err = loadCheckpoint(*checkpointPath)
+		if err != nil {
+			fatalf("❌ Failed to read checkpoint: %v", err)
+		}
+	}
+
+	for _, parts := range demos {
+		path := parts[0]
+		var key string
+		if *checkpointPath != "" {
+			hash, err := fileHash(path)
+			if err != nil {
+				fatalf("❌ Failed to open demo: %v", err)
+			}
+			key = checkpointKey(hash, path)
+			if completed[key] {
+				fmt.Printf("⏭️  Skipping %s, already exported according to the checkpoint\n", path)
+				continue
+			}
+		}
+
+		resetDemoState()
+		if err := exportDemo(parts, *outDir); err != nil {
+			fatalf("❌ %v", err)
+		}
+
+		if *checkpointPath != "" {
+			if err := appendCheckpoint(*checkpointPath, key); err != nil {
+				fatalf("❌ Failed to update checkpoint: %v", err)
+			}
+		}
+	}
+
+	if exportLeaderboard {
+		writeLeaderboard(joinOutputPath(*outDir, "leaderboard.csv"))
+		if outputErr != nil {
+			fatalf("❌ %v", outputErr)
+		}
+	}
+
+	if failOnUnexpected && unexpectedDemos > 0 {
+		fatalf("❌ %d demos had players not in -expected-steamids", unexpectedDemos)
+	}
+}
+
+// collectDemos returns the demos to export: the positional arguments if any,
+// otherwise -demo. Directories expand to the .dem files inside them.
+func collectDemos(demoFlag string, args []string) ([]string, error) {
+	if len(args) == 0 {
+		args = []string{demoFlag}
+	}
+
+	var demos []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil || !info.IsDir() {
+			demos = append(demos, arg)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(arg, "*.dem"))
+		if err != nil {
+			return nil, err
+		}
+		demos = append(demos, matches...)
 	}
+	if len(demos) == 0 {
+		return nil, fmt.Errorf("no .dem files in %s", strings.Join(args, ", "))
+	}
+	return demos, nil
+}
+
+// givenFlags returns the names of the flags set on the command line
+func givenFlags(fs *flag.FlagSet) map[string]bool {
+	given := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+	return given
+}
 
-	f, err := os.Open(*demoPath)
+// resetDemoState clears everything tracked while exporting a demo, so the
+// next demo of a batch starts from scratch. Cross-demo state such as the
+// leaderboard is left alone.
+func resetDemoState() {
+	currentRound = 0
+	currentFile, currentWriter = nil, nil
+	baseFile, baseWriter = nil, nil
+	lastTick = 0
+	clear(framePoses)
+	idleSince, idleStopped = -1, false
+	roundsEnded, limitReached = 0, false
+	matchEnded, matchEndTick = false, -1
+	lastFlushTick = 0
+	clear(openWriters)
+	playerDir = ""
+
+	roundPhase, phaseStartTick, phaseLength = phaseOver, 0, 0
+	roundTick, lastRoundTicked, liveStartTick = -1, 0, -1
+	framesSinceStart, framesSinceRound = 0, 0
+	roundStartTick = -1
+	roundInProgress, roundStartPlayed = false, 0
+	clear(roundOutputs)
+	nextInterval, intervalBoundary = 0, 0
+	lastInfernoTick = 0
+	clear(prevPoses)
+	skippedRows = 0
+	outOfBoundsRows = 0
+	normalizeBox, normalizedClamped = nil, 0
+	calloutsReady = false
+	objectivesReady = false
+	clear(warnedSteamIDs)
+	clear(lastAlivePose)
+	knifeRoundChecked, skippingRound = false, false
+	clear(recentDamage)
+	openingDone = false
+	clear(lastMovement)
+	clear(lastZoom)
+	clear(lastPositions)
+	clear(lastYaw)
+	clear(lastSamples)
+	clear(flashedBy)
+	clear(startingSides)
+	clear(botOwners)
+	clear(orphanedSlots)
+	clear(sprays)
+	round = roundRecord{}
+	roundSummaries = nil
+	clear(survivingValue)
+	winStreak = 0
+	bombLatched = ""
+	clear(lastMoney)
+	clear(activities)
+	clear(roundUtility)
+	clear(matchUtility)
+	activityOrder = nil
+	clear(moneyReasons)
+	pendingWindows, killCount = nil, 0
+	outcomeState, outcomeBuffers = outcomeDrop, nil
+	metaNotes = nil
+	clear(demoNameIDs)
+	clear(demoStats)
+	clear(unexpectedPlayers)
+	clear(inContact)
+	clear(pendingContacts)
+	resetPseudonyms()
+}
+
+// exportDemo parses one demo and writes its outputs into a folder named
+// after it inside outDir. A demo split into several parts is parsed part by
+// part into the same outputs and named after the first one.
+func exportDemo(parts []string, outDir string) (err error) {
+	demoPath := parts[0]
+
+	// Outputs closed by the deferred calls below can still fail, so this
+	// has to run last
+	defer func() {
+		if err == nil {
+			err = outputErr
+		}
+	}()
+
+	// Prepare output folder name (based on demo file, without extension)
+	demoName = strings.TrimSuffix(filepath.Base(demoPath), filepath.Ext(demoPath))
+	outputFolder = joinOutputPath(outDir, demoName)
+	outputErr = nil
+
+	err = prepareOutputFolder(outputFolder)
 	if err != nil {
-		log.Fatal("❌ Failed to open demo:", err)
+		return fmt.Errorf("%w: creating output folder: %v", ErrWrite, err)
 	}
-	defer f.Close()
 
-	p := dem.NewParser(f)
+	var p dem.Parser
+	if len(parts) > 1 {
+		p, err = newPartsParser(parts)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrDemoOpen, err)
+		}
+	} else {
+		f, err := os.Open(demoPath)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrDemoOpen, err)
+		}
+		defer f.Close()
+		p = dem.NewParserWithConfig(f, parserConfig())
+	}
 
-	// If not splitting rounds, open a single CSV upfront
-	if !splitRounds {
-		baseFile, baseWriter = openCSV(filepath.Join(outputFolder, "all_ticks.csv"))
-		defer closeCSV(baseFile, baseWriter)
+	// Player files go straight into the output folder unless they are nested
+	// in per-round folders
+	if splitPlayers && !splitRounds {
+		playerDir = outputFolder
 	}
 
 	// Register handlers
+	if roundOutcomes != nil {
+		registerOutcomeHandlers(p)
+	}
+
+	registerRestartHandlers(p)
+
 	p.RegisterEventHandler(func(e events.RoundStart) {
+		// A restarted round keeps its number, its output and knife status
+		if isRoundRestart(p.GameState()) {
+			fmt.Printf("🔁 Round %d restarted, continuing its output\n", currentRound)
+			return
+		}
+
+		skippingRound = false
+
+		// Warmup restarts aren't real rounds
+		if p.GameState().IsWarmupPeriod() {
+			return
+		}
+
+		// Only the first real round can be the knife round
+		if skipKnifeRound && !knifeRoundChecked && !p.GameState().IsWarmupPeriod() {
+			knifeRoundChecked = true
+			if isKnifeRound(p.GameState()) {
+				skippingRound = true
+				markRoundStart(p.GameState())
+				fmt.Println("🔪 Knife round detected, skipping it")
+				return
+			}
+		}
+
+		next := nextRoundNumber(p.GameState())
+		if next <= currentRound {
+			fmt.Printf("⏪ Round %d is being replayed after a backup restore\n", next)
+		}
+		currentRound = next
+		markRoundStart(p.GameState())
 		if splitRounds {
-			startNewRound()
+			startNewRound(p)
 		}
 	})
 
 	p.RegisterEventHandler(func(e events.FrameDone) {
+		// Cancelled at the last round's end, the rest of the frame is past it
+		if limitReached {
+			return
+		}
+
+		// No point parsing on once an output is broken
+		if outputErr != nil {
+			p.Cancel()
+			return
+		}
+
 		gs := p.GameState()
 		tick := gs.IngameTick()
 
-		// Avoid duplicate ticks
-		if tick == lastTick {
+		if idleTimeout > 0 && idleExpired(tick, tickRate(p)) {
+			idleStopped = true
+			p.Cancel()
+			return
+		}
+
+		// Avoid duplicate ticks, unless sub-tick frames were asked for
+		if duplicateFrame(tick, gs.Participants().Playing()) {
 			return
 		}
-		lastTick = tick
+		if expectedSteamIDs != nil {
+			checkSteamIDs(gs.Participants().Playing())
+		}
 
-		for _, player := range gs.Participants().Playing() {
-			if splitRounds && currentWriter != nil {
-				writePlayerData(currentWriter, tick, player)
-			} else if !splitRounds && baseWriter != nil {
-				writePlayerData(baseWriter, tick, player)
+		if flushEvery > 0 && tick-lastFlushTick >= flushEvery {
+			flushOpenWriters()
+			lastFlushTick = tick
+		}
+
+		if skippingRound || matchEnded {
+			return
+		}
+
+		rate := tickRate(p)
+		if interpolate {
+			defer rememberPoses(gs.Participants().Playing(), tick)
+		}
+		if intervalSeconds > 0 && !onInterval(tick, rate) {
+			return
+		}
+		// rounds.csv still wants its sample counts
+		if summaryOnly {
+			countSample(tick)
+			return
+		}
+
+		// If not splitting rounds, everything goes to a single file. It's
+		// opened on the first frame, once the header has told us the map.
+		if !splitRounds && !splitPlayers && baseWriter == nil {
+			openBaseFile(p)
+		}
+		if normalizePositions && normalizeBox == nil {
+			if err := setupNormalize(p); err != nil {
+				outputErr = err
+				return
 			}
 		}
+
+		if callouts != nil && !calloutsReady {
+			callouts.useMap(p.Header().MapName)
+		}
+		if objectives != nil && !objectivesReady {
+			objectives.useMap(p.Header().MapName)
+		}
+
+		ctx := tickContext{
+			tick:          tick,
+			frame:         p.CurrentFrame(),
+			rate:          rate,
+			gameTime:      gameTimeSeconds(tick, rate),
+			timeRemaining: roundTimeRemaining(tick, rate),
+		}
+		if interpolate {
+			// The row stands for the boundary, not the tick it was taken on
+			ctx.gameTime = intervalBoundary / rate
+		}
+		countSample(tick)
+		ctx.roundTick = advanceRoundTick(tick)
+
+		// The gates below only leave frames out of the tick rows, the
+		// trackers further down still see them
+		writeRows := true
+		if skipFirstFrames > 0 && settling() {
+			writeRows = false
+		}
+		if roundStartDelay > 0 && inRoundStartDelay(tick) {
+			writeRows = false
+		}
+		if postPlantOnly && !inPostPlant(tick, rate) {
+			writeRows = false
+		}
+
+		players := gs.Participants().Playing()
+		ctx.players = players
+		if !positionsOnly {
+			ctx.nearest = nearestEnemies(players)
+		}
+		if objectives != nil {
+			objectives.update(gs, players)
+		}
+		if writeRows {
+			for _, player := range players {
+				if splitPlayers {
+					if w := playerWriter(player); w != nil {
+						writePlayerData(w, ctx, player)
+					}
+				} else if splitRounds && currentWriter != nil {
+					writePlayerData(currentWriter, ctx, player)
+				} else if !splitRounds && baseWriter != nil {
+					writePlayerData(baseWriter, ctx, player)
+				}
+			}
+		}
+
+		if windowTicks > 0 {
+			captureWindowTick(ctx, players)
+		}
+		if exportMovement {
+			trackMovement(ctx, players)
+		}
+		if exportZoom {
+			trackZoom(ctx, players)
+		}
+		if exportBomb {
+			trackBomb(ctx, gs)
+		}
+		if exportEconomy {
+			trackEconomy(ctx, players)
+		}
+		if exportActivity {
+			trackActivity(ctx, players)
+		}
+		if exportVisibility {
+			trackVisibility(ctx, players)
+		}
+		if exportTeamPositions {
+			trackTeamPositions(ctx, players)
+		}
+		if exportSpotted {
+			trackSpotted(ctx, players)
+		}
+		if exportInfernos {
+			trackInfernos(ctx, gs)
+		}
 	})
 
+	registerPhaseHandlers(p)
+	registerTeleportHandlers(p)
+	registerFlashHandlers(p)
+	registerRecoilHandlers(p)
+	registerBotHandlers(p)
+
+	if skipFirstFrames > 0 && skipPerRound || roundStartDelay > 0 {
+		registerSettleHandlers(p)
+	}
+
+	if idleTimeout > 0 {
+		registerIdleHandlers(p)
+	}
+
+	if !keepPastMatchEnd {
+		registerMatchEndHandlers(p)
+	}
+
+	if throttleMemory {
+		registerThrottleHandlers(p)
+	}
+
+	if carryDead {
+		registerCarryHandlers(p)
+	}
+
+	if positionDeltas {
+		registerDeltaHandlers(p)
+	}
+
+	if unwrapView {
+		registerViewHandlers(p)
+	}
+
+	if exportItems {
+		registerItemHandlers(p)
+		defer closeOutput(itemsFile, itemsWriter)
+	}
+
+	if exportMovement {
+		openMovementEvents()
+		defer closeOutput(movementFile, movementWriter)
+	}
+
+	if exportZoom {
+		openZoomEvents()
+		defer closeOutput(zoomFile, zoomWriter)
+	}
+
+	if textLog {
+		registerTextLogHandlers(p)
+		defer closeOutput(textLogFile, textLogOut)
+	}
+
+	if exportUtility {
+		registerUtilityHandlers(p)
+		defer closeOutput(utilityFile, utilityWriter)
+	}
+
+	if exportActivity {
+		registerActivityHandlers(p)
+		defer closeOutput(activityFile, activityWriter)
+	}
+
+	if exportEconomy {
+		registerEconomyHandlers(p)
+		defer closeOutput(economyFile, economyWriter)
+	}
+
+	if exportInfernos {
+		registerInfernoHandlers(p)
+		defer closeOutput(infernosFile, infernosWriter)
+	}
+
+	if exportSpotted {
+		openSpotted()
+		defer closeOutput(spottedFile, spottedWriter)
+	}
+
+	if exportTeamPositions {
+		openTeamPositions()
+		defer closeOutput(teamPositionsFile, teamPositionsWriter)
+	}
+
+	if exportVisibility {
+		openVisibility()
+		defer closeOutput(visibilityFile, visibilityWriter)
+	}
+
+	if exportReactions {
+		registerReactionHandlers(p)
+		defer closeOutput(reactionsFile, reactionsWriter)
+	}
+
+	if exportBomb {
+		registerBombHandlers(p)
+		defer closeOutput(bombFile, bombWriter)
+	}
+
+	if exportKills {
+		registerKillHandlers(p)
+		defer closeOutput(killsFile, killsWriter)
+	}
+
+	if exportDamage {
+		registerDamageHandlers(p)
+		defer closeOutput(damageFile, damageWriter)
+	}
+
+	if exportGrenades {
+		registerGrenadeHandlers(p)
+		defer closeOutput(grenadesFile, grenadesWriter)
+	}
+
+	if exportOpenings {
+		registerOpeningHandlers(p)
+		defer closeOutput(openingsFile, openingsWriter)
+	}
+
+	if exportRounds || roundsJSON {
+		registerRoundHandlers(p)
+		defer closeOutput(roundsFile, roundsWriter)
+	}
+	if roundsJSON {
+		defer writeRoundsJSON()
+	}
+
+	if exportPhases {
+		registerPhaseEventHandlers(p)
+		defer closeOutput(phasesFile, phasesWriter)
+	}
+
+	if exportScoreboard {
+		registerScoreboardHandlers(p)
+		defer closeOutput(scoreboardFile, scoreboardWriter)
+	}
+
+	if windowTicks > 0 {
+		registerWindowHandlers(p)
+		defer closeOutput(windowFile, windowWriter)
+	}
+
+	if exportLeaderboard || summaryOnly {
+		registerLeaderboardHandlers(p)
+	}
+
+	// Last, so the final round's RoundEnd reaches the exporters first
+	if limitRounds > 0 {
+		registerRoundLimitHandlers(p)
+	}
+
 	// Parse the demo
 	err = p.ParseToEnd()
+	if outputErr != nil {
+		return outputErr
+	}
+	if idleStopped && errors.Is(err, dem.ErrCancelled) {
+		reportIdleStop(p)
+		err = nil
+	}
+	if limitReached && errors.Is(err, dem.ErrCancelled) {
+		err = nil
+	}
+	if limitRounds > 0 {
+		reportRoundLimit()
+	}
 	if err != nil {
-		log.Fatalf("❌ Error during parsing: %v", err)
+		return fmt.Errorf("%w: %v", ErrParse, err)
 	}
 
 	// Final cleanup
-	if splitRounds {
+	if splitPlayers {
+		closePlayerFiles()
+	} else if splitRounds {
 		closeCurrentRound()
+	} else if !summaryOnly {
+		if baseWriter == nil {
+			openBaseFile(p)
+		}
+		closeOutput(baseFile, baseWriter)
 	}
 
+	reportNormalize()
+	if targetTickRate > 0 {
+		reportTargetTickRate(tickRate(p))
+	}
+	if skippedRows > 0 {
+		log.Printf("⚠️  Skipped %d rows with missing entity data", skippedRows)
+	}
+	if outOfBoundsRows > 0 {
+		action := "Skipped"
+		if boundsMode == "clamp" {
+			action = "Clamped"
+		}
+		log.Printf("⚠️  %s %d rows outside -clamp-bounds", action, outOfBoundsRows)
+	}
+
+	if currentRound == 0 {
+		log.Printf("⚠️  No rounds were played in %s, it looks like a warmup-only demo", demoName)
+		metaNotes = append(metaNotes, "no rounds were played, the demo only contains warmup")
+	}
+	if compactNames {
+		writeNameMap()
+	}
+	reportUnexpectedSteamIDs()
+	if pseudonyms {
+		writePseudonymMap()
+	}
+	if summaryOnly {
+		writePlayerTotals(demoStats, outputPath("player_stats.csv"))
+		metaNotes = append(metaNotes, "exported with -summary-only, there are no tick files")
+	}
+	writeMeta(p)
+
+	if outputErr != nil {
+		return outputErr
+	}
 	fmt.Printf("✅ Done! Output written to folder: %s\n", outputFolder)
+	return nil
 }
 
-func startNewRound() {
+func openBaseFile(p dem.Parser) {
+	path := outputPath(renderFilename(tickFilename, p, currentRound))
+	if maxFileSize > 0 {
+		baseFile, baseWriter = openRotatingTickWriter(path)
+		return
+	}
+	baseFile, baseWriter = openTickWriter(path)
+}
+
+func startNewRound(p dem.Parser) {
+	if splitPlayers {
+		startPlayerRound()
+		return
+	}
+
 	// Close previous round file if open
 	closeCurrentRound()
 
+	// With an outcome filter the file is only created at RoundEnd, once we
+	// know the round is wanted; until then its rows are held in memory
+	if roundOutcomes != nil && outcomeState == outcomePending {
+		currentWriter = &rowBuffer{}
+		return
+	}
+
 	// Build file path in the output folder
-	filename := fmt.Sprintf("round_%d.csv", currentRound)
-	fullPath := filepath.Join(outputFolder, filename)
+	filename := renderFilename(roundFilename, p, currentRound)
+	if suffix := roundOutputSuffix(currentRound); suffix != "" {
+		ext := tickFileExt()
+		filename = strings.TrimSuffix(filename, ext) + suffix + ext
+	}
+	fullPath := outputPath(filename)
 
-	file, writer := openCSV(fullPath)
+	file, writer := openTickWriter(fullPath)
 	currentFile = file
 	currentWriter = writer
 
 	fmt.Printf("➡️  Started round %d → writing to %s\n", currentRound, fullPath)
-
-	currentRound++
 }
 
 func closeCurrentRound() {
-	closeCSV(currentFile, currentWriter)
+	if fsyncRounds {
+		syncOutput(currentFile, currentWriter)
+	}
+	closeOutput(currentFile, currentWriter)
 	currentFile = nil
 	currentWriter = nil
 }
 
-func openCSV(path string) (*os.File, *csv.Writer) {
-	file, err := os.Create(path)
-	if err != nil {
-		log.Fatalf("❌ Failed to create CSV file: %v", err)
-	}
-	writer := csv.NewWriter(file)
-	writer.Write([]string{
-		"tick", "player_name",
-		"pos_x", "pos_y", "pos_z",
-		"view_dir_x", "view_dir_y",
-		"is_ducking", "is_ducking_in_progress", "is_unducking_in_progress", "is_standing",
-	})
+// tickSchemaVersion identifies the tick file layout. Bump it whenever
+// tickHeader changes so consumers can tell formats apart via meta.json.
+const tickSchemaVersion = 10
+
+// tickHeader is the column layout of the per-tick player files
+var tickHeader = []string{
+	"tick", "player_name",
+	"pos_x", "pos_y", "pos_z",
+	"view_dir_x", "view_dir_y",
+	"is_ducking", "is_ducking_in_progress", "is_unducking_in_progress", "is_standing",
+	"round_time_remaining", "phase",
+	"alive", "game_time_seconds",
+	"teleport", "flashed_by",
+	"frame", "round_tick",
+	"money_spent",
+	"nearest_enemy_dist", "nearest_enemy_steamid",
+	"recoil_index", "has_position",
+	"side", "starting_side",
+	"aiming_at_enemy",
+	"is_bot_takeover", "original_steamid",
+	"aimpunch_yaw", "aimpunch_pitch",
+}
+
+// recordWriter writes rows of the tick export. *csv.Writer implements it, as
+// do the writers for the other -format options.
+type recordWriter interface {
+	Write(record []string) error
+	Flush()
+}
+
+// tickFileExt returns the file extension matching -format
+func tickFileExt() string {
+	if outputFormat == "protobuf" {
+		return ".pb"
+	}
+	return ".csv"
+}
+
+// openWriters holds every writer that hasn't been closed yet, so -flush-every
+// can reach them all
+var openWriters = map[recordWriter]bool{}
+
+func flushOpenWriters() {
+	for writer := range openWriters {
+		writer.Flush()
+	}
+}
+
+// openTickWriter opens a tick file in the format selected by -format
+func openTickWriter(path string) (io.WriteCloser, recordWriter) {
+	if outputFormat == "protobuf" {
+		file := openOutput(path)
+		return file, trackWriter(newProtoWriter(file, tickHeader))
+	}
+	return openCSV(path, tickHeader)
+}
+
+func openCSV(path string, header []string) (io.WriteCloser, recordWriter) {
+	file, writer := createCSV(path, header)
+	return file, trackWriter(writer)
+}
+
+// createCSV opens a CSV file that bypasses -flush-every and the round
+// filters, for files written in one go once parsing is over.
+func createCSV(path string, header []string) (io.WriteCloser, recordWriter) {
+	file := openOutput(path)
+	var writer recordWriter = csv.NewWriter(file)
+	writer.Write(header)
+	if nullToken != "" {
+		writer = &nullWriter{recordWriter: writer}
+	}
 	return file, writer
 }
 
-func closeCSV(file *os.File, writer *csv.Writer) {
+// trackWriter registers a newly opened writer for -flush-every and, when
+// -round-outcome is set, routes it through the round outcome filter.
+func trackWriter(writer recordWriter) recordWriter {
+	if roundOutcomes != nil {
+		writer = newOutcomeWriter(writer)
+	}
+	openWriters[writer] = true
+	return writer
+}
+
+func closeOutput(file io.WriteCloser, writer recordWriter) {
 	if writer != nil {
 		writer.Flush()
+		delete(openWriters, writer)
+		releaseOutcomeWriter(writer)
 	}
 	if file != nil {
-		file.Close()
+		// Remote outputs are only finalized here, so this can't be ignored
+		if err := file.Close(); err != nil {
+			failOutput("finalizing", err)
+		}
 	}
 }
 
+// tickContext holds what all rows written for a sampled tick have in common
+type tickContext struct {
+	tick          int
+	frame         int
+	roundTick     int
+	nearest       map[*common.Player]enemyDistance
+	players       []*common.Player
+	rate          float64
+	gameTime      float64
+	timeRemaining float64
+}
+
+// gameTimeSeconds converts a tick to seconds since the start of the demo.
+// Every file uses this, so they can be joined on game_time_seconds.
+func gameTimeSeconds(tick int, rate float64) float64 {
+	return float64(tick) / rate
+}
+
+func formatSeconds(s float64) string {
+	return fmt.Sprintf("%.3f", s)
+}
+
+// tickRate returns the demo's tick rate, falling back to 64 (the CS2 default)
+// while the parser hasn't seen the server info yet.
+func tickRate(p dem.Parser) float64 {
+	if rate := p.TickRate(); rate > 0 {
+		return rate
+	}
+	return 64
+}
+
 func boolToIntString(b bool) string {
 	if b {
 		return "1"
@@ -149,20 +1115,101 @@ func boolToIntString(b bool) string {
 	return "0"
 }
 
-func writePlayerData(writer *csv.Writer, tick int, player *common.Player) {
-	pos := player.Position()
+func writePlayerData(writer recordWriter, ctx tickContext, player *common.Player) {
+	row := providerValues(ctx, player)
+	if row == nil {
+		return
+	}
+	if pseudonyms {
+		pseudonymizeRow(tickHeader, row, player)
+	}
+	round.rows++
+	// Typed formats reject values their fields can't hold
+	if err := writer.Write(row); err != nil {
+		failOutput(fmt.Sprintf("tick %d of %s", ctx.tick, player.Name), err)
+	}
+}
+
+// playerValues returns the built-in columns of a player's row, or nil when
+// the row is skipped
+func playerValues(ctx tickContext, player *common.Player) []string {
+	if strict {
+		if reason := missingData(player); reason != "" {
+			skipIncompleteRow(ctx.tick, player, reason)
+			return nil
+		}
+	}
+
+	pose := poseOf(player)
+	if interpolate {
+		pose = interpolatedPose(ctx, player, pose)
+	}
+	alive := player.IsAlive()
+	teleport := isTeleport(ctx, player, alive)
+	if carryDead {
+		pose = carryPose(player, alive, pose)
+	}
+	if clampBounds != nil && !clampBounds.contains(pose.pos) {
+		outOfBoundsRows++
+		if boundsMode == "skip" {
+			return nil
+		}
+		pose.pos = clampBounds.clamp(pose.pos)
+	}
+	posFormat := "%.2f"
+	if normalizePositions {
+		pose.pos = normalize(pose.pos)
+		posFormat = "%.5f"
+	}
+	if positionDeltas {
+		pose.pos = positionDelta(playerKey(player), pose.pos)
+	}
+	if unwrapView {
+		pose.viewX = unwrapYaw(playerKey(player), pose.viewX)
+	}
+
+	enemyDist, enemyID := nearestEnemyColumns(ctx, player)
+	takeover, originalID := botTakeoverColumns(ctx, player)
+	punchYaw, punchPitch := aimPunch(player)
 
-	writer.Write([]string{
-		strconv.Itoa(tick),
-		player.Name,
-		fmt.Sprintf("%.2f", pos.X),
-		fmt.Sprintf("%.2f", pos.Y),
-		fmt.Sprintf("%.2f", pos.Z),
-		fmt.Sprintf("%.4f", player.ViewDirectionX()),
-		fmt.Sprintf("%.4f", player.ViewDirectionY()),
+	name := player.Name
+	if compactNames {
+		name = strconv.Itoa(compactID(player))
+	}
+
+	return []string{
+		strconv.Itoa(ctx.tick),
+		name,
+		fmt.Sprintf(posFormat, pose.pos.X),
+		fmt.Sprintf(posFormat, pose.pos.Y),
+		fmt.Sprintf(posFormat, pose.pos.Z),
+		fmt.Sprintf("%.4f", pose.viewX),
+		fmt.Sprintf("%.4f", pose.viewY),
 		boolToIntString(player.IsDucking()),
 		boolToIntString(player.IsDuckingInProgress()),
 		boolToIntString(player.IsUnDuckingInProgress()),
 		boolToIntString(player.IsStanding()),
-	})
+		fmt.Sprintf("%.2f", ctx.timeRemaining),
+		roundPhase,
+		boolToIntString(alive),
+		formatSeconds(ctx.gameTime),
+		boolToIntString(teleport),
+		flasherOf(player),
+		strconv.Itoa(ctx.frame),
+		strconv.Itoa(ctx.roundTick),
+		// The game resets this at round start, so it is exactly the buy
+		// rather than a money delta that kill rewards would skew
+		strconv.Itoa(player.MoneySpentThisRound()),
+		enemyDist,
+		enemyID,
+		recoilIndex(ctx, player),
+		boolToIntString(hasPosition(player)),
+		sideName(player.Team),
+		startingSide(player),
+		boolToIntString(aimingAtEnemy(ctx, player)),
+		takeover,
+		originalID,
+		punchYaw,
+		punchPitch,
+	}
 }
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	keepPastMatchEnd bool
+	// matchEnded stops the tick export once the match is won, leaving out
+	// the GOTV outro; matchEndTick is kept for meta.json
+	matchEnded   bool
+	matchEndTick = -1
+)
+
+func registerMatchEndHandlers(p dem.Parser) {
+	p.RegisterEventHandler(func(e events.AnnouncementWinPanelMatch) {
+		if matchEnded {
+			return
+		}
+		matchEnded = true
+		matchEndTick = p.GameState().IngameTick()
+		fmt.Printf("🏁 Match over at tick %d, leaving out the rest of the demo\n", matchEndTick)
+	})
+	// Another match on the same server picks the export back up
+	p.RegisterEventHandler(func(e events.MatchStart) {
+		matchEnded = false
+	})
+}
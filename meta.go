@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+)
+
+// demoMeta is written to meta.json next to each demo's other outputs. None
+// of the fields are omitempty so the schema stays the same for every demo.
+type demoMeta struct {
+	SchemaVersion int `json:"schema_version"`
+	// Flags like -deltas add columns, so list what this export actually has
+	TickColumns []string `json:"tick_columns"`
+
+	Demo       string  `json:"demo"`
+	Map        string  `json:"map"`
+	TickRate   float64 `json:"tick_rate"`
+	LastTick   int     `json:"last_tick"`
+	Rounds     int     `json:"rounds"`
+	WarmupOnly bool    `json:"warmup_only"`
+	// Rows per second asked for with -target-tickrate, 0 without it
+	TargetTickRate float64 `json:"target_tick_rate"`
+
+	// Where the export was cut at the match end, null if it wasn't
+	MatchEndTick *int `json:"match_end_tick"`
+
+	// From the demo header
+	ServerName     string  `json:"server_name"`
+	ClientName     string  `json:"client_name"`
+	PlaybackTime   float64 `json:"playback_time"`
+	PlaybackTicks  int     `json:"playback_ticks"`
+	PlaybackFrames int     `json:"playback_frames"`
+	SignonLength   int     `json:"signon_length"`
+
+	Notes []string `json:"notes"`
+}
+
+var metaNotes []string
+
+func writeMeta(p dem.Parser) {
+	h := p.Header()
+	meta := demoMeta{
+		SchemaVersion: tickSchemaVersion,
+		TickColumns:   tickHeader,
+
+		Demo:       demoName,
+		Map:        h.MapName,
+		TickRate:   tickRate(p),
+		LastTick:   lastTick,
+		Rounds:     currentRound,
+		WarmupOnly: currentRound == 0,
+
+		TargetTickRate: targetTickRate,
+
+		ServerName:     h.ServerName,
+		ClientName:     h.ClientName,
+		PlaybackTime:   h.PlaybackTime.Seconds(),
+		PlaybackTicks:  h.PlaybackTicks,
+		PlaybackFrames: h.PlaybackFrames,
+		SignonLength:   h.SignonLength,
+
+		Notes: metaNotes,
+	}
+	if matchEndTick >= 0 {
+		meta.MatchEndTick = &matchEndTick
+	}
+	if meta.Notes == nil {
+		meta.Notes = []string{}
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		failOutput("encoding meta.json", err)
+		return
+	}
+
+	file := openOutput(outputPath("meta.json"))
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		failOutput("meta.json", err)
+	}
+	closeOutput(file, nil)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+type movementState struct {
+	ducking  bool
+	airborne bool
+}
+
+var (
+	exportMovement bool
+	movementFile   io.WriteCloser
+	movementWriter recordWriter
+	lastMovement   = map[string]movementState{}
+)
+
+func openMovementEvents() {
+	movementFile, movementWriter = openCSV(outputPath("movement_events.csv"), []string{
+		"tick", "game_time_seconds", "player", "transition",
+	})
+}
+
+// trackMovement compares each player's ducking and airborne flags with the
+// previous sampled tick and writes a row for every change. Leaving the ground
+// is reported as a jump, so walking off a ledge shows up as one too.
+func trackMovement(ctx tickContext, players []*common.Player) {
+	for _, player := range players {
+		key := playerKey(player)
+		if !player.IsAlive() {
+			delete(lastMovement, key)
+			continue
+		}
+
+		now := movementState{ducking: player.IsDucking(), airborne: player.IsAirborne()}
+		prev, seen := lastMovement[key]
+		lastMovement[key] = now
+		if !seen {
+			continue
+		}
+
+		if now.ducking != prev.ducking {
+			writeMovementEvent(ctx, player, pick(now.ducking, "crouch_start", "crouch_end"))
+		}
+		if now.airborne != prev.airborne {
+			writeMovementEvent(ctx, player, pick(now.airborne, "jump", "land"))
+		}
+	}
+}
+
+func writeMovementEvent(ctx tickContext, player *common.Player, transition string) {
+	movementWriter.Write([]string{
+		strconv.Itoa(ctx.tick),
+		formatSeconds(ctx.gameTime),
+		player.Name,
+		transition,
+	})
+}
+
+func pick(cond bool, ifTrue, ifFalse string) string {
+	if cond {
+		return ifTrue
+	}
+	return ifFalse
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+type nameEntry struct {
+	id      int
+	name    string
+	steamID uint64
+}
+
+var (
+	compactNames bool
+
+	// compactIDs stays the same for the whole run, so ids match across the
+	// demos of a batch
+	compactIDs = map[string]*nameEntry{}
+
+	// demoNameIDs are the entries seen in the current demo
+	demoNameIDs = map[string]*nameEntry{}
+)
+
+// compactID returns the small integer id standing in for player
+func compactID(player *common.Player) int {
+	key := playerKey(player)
+	e, ok := compactIDs[key]
+	if !ok {
+		e = &nameEntry{id: len(compactIDs) + 1, steamID: player.SteamID64}
+		compactIDs[key] = e
+	}
+	e.name = player.Name
+	demoNameIDs[key] = e
+	return e.id
+}
+
+// compactHeader swaps the player_name column for player_id
+func compactHeader(header []string) []string {
+	out := make([]string, len(header))
+	for i, col := range header {
+		if col == "player_name" {
+			col = "player_id"
+		}
+		out[i] = col
+	}
+	return out
+}
+
+// writeNameMap writes the ids used in the current demo to name_map.csv
+func writeNameMap() {
+	entries := make([]*nameEntry, 0, len(demoNameIDs))
+	for _, e := range demoNameIDs {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].id < entries[j].id })
+
+	file, writer := createCSV(outputPath("name_map.csv"), []string{"player_id", "player_name", "steamid"})
+	for _, e := range entries {
+		writer.Write([]string{
+			strconv.Itoa(e.id),
+			e.name,
+			strconv.FormatUint(e.steamID, 10),
+		})
+	}
+	closeOutput(file, writer)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+type enemyDistance struct {
+	dist  float64
+	enemy *common.Player
+}
+
+// nearestEnemies finds the closest living enemy of every living player. It
+// runs over the whole tick before any row is written, since each row needs
+// everyone else's position.
+func nearestEnemies(players []*common.Player) map[*common.Player]enemyDistance {
+	nearest := make(map[*common.Player]enemyDistance, len(players))
+	for _, a := range players {
+		if !a.IsAlive() {
+			continue
+		}
+		best := enemyDistance{dist: math.Inf(1)}
+		for _, b := range players {
+			if !b.IsAlive() || !isEnemy(a, b) {
+				continue
+			}
+			if d := a.Position().Distance(b.Position()); d < best.dist {
+				best = enemyDistance{dist: d, enemy: b}
+			}
+		}
+		if best.enemy != nil {
+			nearest[a] = best
+		}
+	}
+	return nearest
+}
+
+func isEnemy(a, b *common.Player) bool {
+	switch a.Team {
+	case common.TeamTerrorists:
+		return b.Team == common.TeamCounterTerrorists
+	case common.TeamCounterTerrorists:
+		return b.Team == common.TeamTerrorists
+	}
+	return false
+}
+
+// nearestEnemyColumns returns the nearest_enemy_dist and
+// nearest_enemy_steamid values, blank when no enemy is alive.
+func nearestEnemyColumns(ctx tickContext, player *common.Player) (string, string) {
+	n, ok := ctx.nearest[player]
+	if !ok {
+		return "", ""
+	}
+	return fmt.Sprintf("%.2f", n.dist), strconv.FormatUint(n.enemy.SteamID64, 10)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/golang/geo/r3"
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+)
+
+// mapBounds holds the playable area of the competitive maps. X and Y come
+// from the radar overviews; Z is approximate, so off-the-map heights clamp.
+// Use -normalize-bounds for anything else.
+var mapBounds = map[string]bounds{
+	"de_dust2":    radarBounds(-2476, 3239, 4.4, -250, 350),
+	"de_mirage":   radarBounds(-3230, 1713, 5.0, -400, 100),
+	"de_inferno":  radarBounds(-2087, 3870, 4.9, -100, 500),
+	"de_nuke":     radarBounds(-3453, 2887, 7.0, -800, 200),
+	"de_overpass": radarBounds(-4831, 1781, 5.2, -600, 600),
+	"de_vertigo":  radarBounds(-3168, 1762, 4.0, 11000, 12500),
+	"de_ancient":  radarBounds(-2953, 2164, 5.0, -300, 300),
+	"de_anubis":   radarBounds(-2796, 3328, 5.22, -300, 300),
+	"de_train":    radarBounds(-2308, 2078, 4.082, -400, 300),
+}
+
+// radarBounds turns a radar overview's top left corner and scale (the radar
+// is 1024 pixels wide) into world bounds
+func radarBounds(posX, posY, scale, minZ, maxZ float64) bounds {
+	size := 1024 * scale
+	return bounds{
+		min: r3.Vector{X: posX, Y: posY - size, Z: minZ},
+		max: r3.Vector{X: posX + size, Y: posY, Z: maxZ},
+	}
+}
+
+var (
+	normalizePositions bool
+	// normalizeOverride comes from -normalize-bounds and wins over the table
+	normalizeOverride *bounds
+	normalizeBox      *bounds
+	normalizedClamped int
+)
+
+// baseMapName strips the prefix of workshop maps, which come as
+// "workshop/<id>/de_name"
+func baseMapName(mapName string) string {
+	return mapName[strings.LastIndex(mapName, "/")+1:]
+}
+
+// setupNormalize picks the bounds for the demo's map once its header is in
+func setupNormalize(p dem.Parser) error {
+	if normalizeOverride != nil {
+		normalizeBox = normalizeOverride
+		return nil
+	}
+	mapName := baseMapName(p.Header().MapName)
+	b, ok := mapBounds[mapName]
+	if !ok {
+		return fmt.Errorf("%w: no bounds known for map %q, pass -normalize-bounds", ErrConfig, mapName)
+	}
+	normalizeBox = &b
+	return nil
+}
+
+// normalize rescales pos into [0,1] on every axis, clamping (and counting)
+// positions outside the bounds
+func normalize(pos r3.Vector) r3.Vector {
+	if !normalizeBox.contains(pos) {
+		normalizedClamped++
+		pos = normalizeBox.clamp(pos)
+	}
+	size := normalizeBox.max.Sub(normalizeBox.min)
+	rel := pos.Sub(normalizeBox.min)
+	return r3.Vector{X: unit(rel.X, size.X), Y: unit(rel.Y, size.Y), Z: unit(rel.Z, size.Z)}
+}
+
+func unit(v, size float64) float64 {
+	if size == 0 {
+		return 0
+	}
+	return v / size
+}
+
+func reportNormalize() {
+	if normalizedClamped > 0 {
+		log.Printf("⚠️  Clamped %d positions outside the map bounds to [0,1]", normalizedClamped)
+	}
+}
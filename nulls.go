@@ -0,0 +1,22 @@
+package main
+
+// nullToken replaces empty CSV fields, e.g. \N for Postgres COPY. Empty
+// fields are how the exporter writes missing data (no team, no nearest enemy,
+// no flasher, ...), so the token marks exactly those.
+var nullToken string
+
+// nullWriter writes nullToken for every empty field of a record
+type nullWriter struct {
+	recordWriter
+	row []string
+}
+
+func (w *nullWriter) Write(record []string) error {
+	w.row = append(w.row[:0], record...)
+	for i, value := range w.row {
+		if value == "" {
+			w.row[i] = nullToken
+		}
+	}
+	return w.recordWriter.Write(w.row)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/golang/geo/r3"
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+// siteCenter averages the positions of players standing on a bomb site.
+// The demo carries no site geometry, but every player's nav place name
+// does say when they are on BombsiteA or BombsiteB.
+type siteCenter struct {
+	sum   r3.Vector
+	count int
+	fixed bool
+}
+
+func (c *siteCenter) add(pos r3.Vector) {
+	if c.fixed {
+		return
+	}
+	c.sum = c.sum.Add(pos)
+	c.count++
+}
+
+func (c *siteCenter) center() (r3.Vector, bool) {
+	if c.count == 0 {
+		return r3.Vector{}, false
+	}
+	return c.sum.Mul(1 / float64(c.count)), true
+}
+
+// objectiveColumns adds dist_to_bomb, dist_to_a and dist_to_b for
+// -objective-distances. The bomb is wherever the parser puts it: on its
+// carrier before the plant, where it lies when dropped and fixed once
+// planted.
+type objectiveColumns struct {
+	// fixedCenters come from -site-centers, by map then "a" / "b"
+	fixedCenters map[string]map[string][3]float64
+
+	bomb    r3.Vector
+	hasBomb bool
+	a, b    siteCenter
+}
+
+var (
+	objectives *objectiveColumns
+	// objectivesReady is cleared per demo so the next one sets up its map
+	objectivesReady bool
+)
+
+func loadSiteCenters(path string) (map[string]map[string][3]float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var centers map[string]map[string][3]float64
+	if err := json.Unmarshal(data, &centers); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return centers, nil
+}
+
+// useMap starts the site centers over for a new demo, fixed to the
+// -site-centers entries of its map if there are any
+func (o *objectiveColumns) useMap(mapName string) {
+	o.a, o.b = siteCenter{}, siteCenter{}
+	sites := o.fixedCenters[baseMapName(mapName)]
+	for name, c := range map[string]*siteCenter{"a": &o.a, "b": &o.b} {
+		if pos, ok := sites[name]; ok {
+			*c = siteCenter{sum: r3.Vector{X: pos[0], Y: pos[1], Z: pos[2]}, count: 1, fixed: true}
+		}
+	}
+	objectivesReady = true
+}
+
+// update takes the bomb position and the players on a site for the frame
+func (o *objectiveColumns) update(gs dem.GameState, players []*common.Player) {
+	bomb := gs.Bomb()
+	o.hasBomb = bomb != nil
+	if bomb != nil {
+		o.bomb = bomb.Position()
+	}
+	for _, player := range players {
+		if !player.IsAlive() {
+			continue
+		}
+		switch player.LastPlaceName() {
+		case "BombsiteA":
+			o.a.add(player.Position())
+		case "BombsiteB":
+			o.b.add(player.Position())
+		}
+	}
+}
+
+func (o *objectiveColumns) Headers() []string {
+	return []string{"dist_to_bomb", "dist_to_a", "dist_to_b"}
+}
+
+// Values leaves a distance empty while its target is unknown: no bomb in
+// the round, or nobody has stood on the site yet
+func (o *objectiveColumns) Values(tick int, p *common.Player) []string {
+	pos := p.Position()
+	row := make([]string, 3)
+	if o.hasBomb {
+		row[0] = fmt.Sprintf("%.2f", pos.Distance(o.bomb))
+	}
+	if c, ok := o.a.center(); ok {
+		row[1] = fmt.Sprintf("%.2f", pos.Distance(c))
+	}
+	if c, ok := o.b.center(); ok {
+		row[2] = fmt.Sprintf("%.2f", pos.Distance(c))
+	}
+	return row
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	exportOpenings bool
+	openingsFile   io.WriteCloser
+	openingsWriter recordWriter
+	openingDone    bool
+)
+
+func registerOpeningHandlers(p dem.Parser) {
+	openingsFile, openingsWriter = openCSV(outputPath("opening_kills.csv"), []string{
+		"tick", "game_time_seconds", "round",
+		"killer", "killer_steamid", "victim", "victim_steamid", "weapon",
+		"killer_x", "killer_y", "killer_z",
+		"victim_x", "victim_y", "victim_z",
+		"side",
+	})
+
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		openingDone = false
+	})
+
+	p.RegisterEventHandler(func(e events.Kill) {
+		if openingDone || skippingRound || currentRound == 0 {
+			return
+		}
+		// Suicides, world damage and team kills don't open a round
+		if e.Killer == nil || e.Victim == nil || e.Killer.Team == e.Victim.Team {
+			return
+		}
+		openingDone = true
+
+		tick := p.GameState().IngameTick()
+		weapon := ""
+		if e.Weapon != nil {
+			weapon = e.Weapon.String()
+		}
+		killerPos := e.Killer.Position()
+		victimPos := e.Victim.Position()
+
+		openingsWriter.Write([]string{
+			strconv.Itoa(tick),
+			formatSeconds(gameTimeSeconds(tick, tickRate(p))),
+			strconv.Itoa(currentRound),
+			e.Killer.Name,
+			playerSteamID(e.Killer),
+			e.Victim.Name,
+			playerSteamID(e.Victim),
+			weapon,
+			fmt.Sprintf("%.2f", killerPos.X),
+			fmt.Sprintf("%.2f", killerPos.Y),
+			fmt.Sprintf("%.2f", killerPos.Z),
+			fmt.Sprintf("%.2f", victimPos.X),
+			fmt.Sprintf("%.2f", victimPos.Y),
+			fmt.Sprintf("%.2f", victimPos.Z),
+			sideName(e.Killer.Team),
+		})
+	})
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+// roundEndReasons names the outcomes accepted by -round-outcome
+var roundEndReasons = map[events.RoundEndReason]string{
+	events.RoundEndReasonTargetBombed:        "target_bombed",
+	events.RoundEndReasonBombDefused:         "bomb_defused",
+	events.RoundEndReasonCTWin:               "ct_win",
+	events.RoundEndReasonTerroristsWin:       "t_win",
+	events.RoundEndReasonDraw:                "draw",
+	events.RoundEndReasonHostagesRescued:     "hostages_rescued",
+	events.RoundEndReasonTargetSaved:         "target_saved",
+	events.RoundEndReasonHostagesNotRescued:  "hostages_not_rescued",
+	events.RoundEndReasonGameStart:           "game_start",
+	events.RoundEndReasonTerroristsSurrender: "t_surrender",
+	events.RoundEndReasonCTSurrender:         "ct_surrender",
+	events.RoundEndReasonTerroristsPlanted:   "t_planted",
+	events.RoundEndReasonCTsReachedHostage:   "ct_reached_hostage",
+}
+
+// What happens to rows written while a -round-outcome filter is active
+const (
+	outcomeDrop = iota
+	outcomePending
+	outcomeKeep
+)
+
+var (
+	// roundOutcomes is the set selected by -round-outcome, nil when unfiltered
+	roundOutcomes  map[events.RoundEndReason]bool
+	emptyRounds    bool
+	outcomeState   = outcomeDrop
+	outcomeBuffers []*outcomeWriter
+)
+
+// parseRoundOutcomes turns a comma separated list of outcome names into the
+// filter set.
+func parseRoundOutcomes(list string) (map[events.RoundEndReason]bool, error) {
+	byName := map[string]events.RoundEndReason{}
+	for reason, name := range roundEndReasons {
+		byName[name] = reason
+	}
+
+	outcomes := map[events.RoundEndReason]bool{}
+	for _, name := range strings.Split(list, ",") {
+		reason, ok := byName[strings.TrimSpace(name)]
+		if !ok {
+			var names []string
+			for n := range byName {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unknown round outcome %q (expected one of %s)", name, strings.Join(names, ", "))
+		}
+		outcomes[reason] = true
+	}
+	return outcomes, nil
+}
+
+// outcomeWriter holds back everything written during a round until its
+// outcome is known, then passes it on or throws it away.
+type outcomeWriter struct {
+	target recordWriter
+	rows   [][]string
+}
+
+func newOutcomeWriter(target recordWriter) *outcomeWriter {
+	w := &outcomeWriter{target: target}
+	outcomeBuffers = append(outcomeBuffers, w)
+	return w
+}
+
+func (w *outcomeWriter) Write(record []string) error {
+	switch outcomeState {
+	case outcomePending:
+		w.rows = append(w.rows, record)
+	case outcomeKeep:
+		return w.target.Write(record)
+	}
+	return nil
+}
+
+func (w *outcomeWriter) Flush() {
+	w.target.Flush()
+}
+
+// releaseOutcomeWriter drops a closed output from outcomeBuffers, which
+// would otherwise keep every per-round writer of the demo around
+func releaseOutcomeWriter(writer recordWriter) {
+	if w, ok := writer.(*outcomeWriter); ok {
+		outcomeBuffers = slices.DeleteFunc(outcomeBuffers, func(b *outcomeWriter) bool { return b == w })
+	}
+}
+
+// rowBuffer collects the tick rows of a round in split mode, where the round
+// file is only created once the round turns out to match.
+type rowBuffer struct {
+	rows [][]string
+}
+
+func (b *rowBuffer) Write(record []string) error {
+	b.rows = append(b.rows, record)
+	return nil
+}
+
+func (b *rowBuffer) Flush() {}
+
+// registerOutcomeHandlers must run before any exporter's RoundEnd handler so
+// rows written at the end of the round already see the decision.
+func registerOutcomeHandlers(p dem.Parser) {
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		outcomeState = outcomePending
+	})
+
+	p.RegisterEventHandler(func(e events.RoundEnd) {
+		keep := roundOutcomes[e.Reason]
+		for _, w := range outcomeBuffers {
+			if keep {
+				for _, row := range w.rows {
+					w.target.Write(row)
+				}
+			}
+			w.rows = nil
+		}
+
+		outcomeState = outcomeDrop
+		if keep {
+			outcomeState = outcomeKeep
+		}
+
+		if splitRounds {
+			buffered, _ := currentWriter.(*rowBuffer)
+			currentWriter = nil
+			if keep && buffered != nil {
+				startNewRound(p)
+				for _, row := range buffered.rows {
+					currentWriter.Write(row)
+				}
+			} else if emptyRounds && buffered != nil {
+				// Header only, so round_1..round_N stays complete
+				startNewRound(p)
+				closeCurrentRound()
+			}
+		}
+	})
+}
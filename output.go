@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+var (
+	s3Client  *s3.Client
+	gcsClient *storage.Client
+)
+
+// isRemoteOutput reports whether path points to a cloud bucket rather than
+// the local filesystem.
+func isRemoteOutput(path string) bool {
+	return strings.HasPrefix(path, "s3://") || strings.HasPrefix(path, "gs://")
+}
+
+// joinOutputPath joins an output directory and a file name. Bucket URLs are
+// joined with forward slashes since filepath.Join would collapse "s3://".
+func joinOutputPath(dir, name string) string {
+	if isRemoteOutput(dir) {
+		return strings.TrimSuffix(dir, "/") + "/" + name
+	}
+	return filepath.Join(dir, name)
+}
+
+// outputPath returns the location of name inside the current output folder
+func outputPath(name string) string {
+	return joinOutputPath(outputFolder, name)
+}
+
+// prepareOutputFolder creates the local output folder. Buckets have no
+// folders, so there is nothing to do for remote outputs.
+func prepareOutputFolder(dir string) error {
+	if isRemoteOutput(dir) {
+		return nil
+	}
+	return os.MkdirAll(dir, os.ModePerm)
+}
+
+// createOutput opens path for writing. Local paths are plain files; s3:// and
+// gs:// URLs stream to the bucket and the object is finalized on Close. With
+// -gzip the output is compressed and gets a .gz suffix.
+func createOutput(path string) (io.WriteCloser, error) {
+	if gzipOutput {
+		file, err := openDestination(path + ".gz")
+		if err != nil {
+			return nil, err
+		}
+		return newGzipFile(file)
+	}
+	return openDestination(path)
+}
+
+func openDestination(path string) (io.WriteCloser, error) {
+	switch {
+	case strings.HasPrefix(path, "s3://"):
+		return newS3Writer(path)
+	case strings.HasPrefix(path, "gs://"):
+		return newGCSWriter(path)
+	}
+	return os.Create(path)
+}
+
+// splitBucketURL splits "s3://bucket/some/key" into its bucket and key
+func splitBucketURL(url string) (bucket, key string, err error) {
+	rest := url[strings.Index(url, "://")+3:]
+	bucket, key, _ = strings.Cut(rest, "/")
+	if bucket == "" || key == "" {
+		return "", "", fmt.Errorf("invalid bucket URL %q", url)
+	}
+	return bucket, key, nil
+}
+
+// s3Writer pipes everything written to it into a multipart upload running
+// in the background.
+type s3Writer struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func newS3Writer(url string) (io.WriteCloser, error) {
+	bucket, key, err := splitBucketURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if s3Client == nil {
+		cfg, err := config.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		s3Client = s3.NewFromConfig(cfg)
+	}
+
+	pr, pw := io.Pipe()
+	w := &s3Writer{pw: pw, done: make(chan error, 1)}
+	go func() {
+		_, err := manager.NewUploader(s3Client).Upload(context.Background(), &s3.PutObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+			Body:   pr,
+		})
+		// Unblock pending writes if the upload failed early
+		pr.CloseWithError(err)
+		w.done <- err
+	}()
+	return w, nil
+}
+
+func (w *s3Writer) Write(b []byte) (int, error) {
+	return w.pw.Write(b)
+}
+
+func (w *s3Writer) Close() error {
+	w.pw.Close()
+	return <-w.done
+}
+
+func newGCSWriter(url string) (io.WriteCloser, error) {
+	bucket, key, err := splitBucketURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if gcsClient == nil {
+		gcsClient, err = storage.NewClient(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GCS client: %w", err)
+		}
+	}
+
+	return gcsClient.Bucket(bucket).Object(key).NewWriter(context.Background()), nil
+}
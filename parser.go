@@ -0,0 +1,23 @@
+package main
+
+import (
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+)
+
+var (
+	msgQueueSize    int
+	noSource1Events bool
+)
+
+// parserConfig builds the demoinfocs configuration from the parser flags.
+//
+// -no-source1-events stops the parser from re-creating the CS:GO style game
+// events that newer CS2 demos no longer contain. That saves work, but on such
+// demos kills.csv, damage.csv, opening_kills.csv, kill_windows.csv and the
+// leaderboard stay empty, and round phases/rounds.csv may be incomplete.
+func parserConfig() dem.ParserConfig {
+	cfg := dem.DefaultParserConfig
+	cfg.MsgQueueBufferSize = msgQueueSize
+	cfg.DisableMimicSource1Events = noSource1Events
+	return cfg
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+// partsParser parses the parts of a split GOTV demo one after another as if
+// they were a single demo. Handlers are registered on every part's parser,
+// and ticks and frames keep counting up across parts.
+type partsParser struct {
+	dem.Parser
+
+	paths    []string
+	file     *os.File
+	handlers []interface{}
+	state    partGameState
+
+	// Totals of the parts parsed so far
+	done        common.DemoHeader
+	frameOffset int
+}
+
+// partGameState shifts the tick of later parts so it continues from where
+// the previous part stopped if the server's tick count was reset.
+type partGameState struct {
+	dem.GameState
+
+	offset  int
+	last    int
+	newPart bool
+}
+
+func (gs *partGameState) IngameTick() int {
+	raw := gs.GameState.IngameTick()
+	if gs.newPart {
+		// Nothing to line up until the new part has a tick
+		if raw == 0 {
+			return gs.last
+		}
+		gs.offset = 0
+		if raw <= gs.last {
+			gs.offset = gs.last + 1 - raw
+		}
+		gs.newPart = false
+	}
+	gs.last = raw + gs.offset
+	return gs.last
+}
+
+func newPartsParser(paths []string) (*partsParser, error) {
+	pp := &partsParser{paths: paths}
+	if err := pp.open(paths[0]); err != nil {
+		return nil, err
+	}
+	return pp, nil
+}
+
+func (pp *partsParser) open(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	pp.file = f
+	pp.Parser = dem.NewParserWithConfig(f, parserConfig())
+	for _, h := range pp.handlers {
+		pp.Parser.RegisterEventHandler(h)
+	}
+	return nil
+}
+
+func (pp *partsParser) RegisterEventHandler(handler interface{}) dem.HandlerIdentifier {
+	pp.handlers = append(pp.handlers, handler)
+	return pp.Parser.RegisterEventHandler(handler)
+}
+
+func (pp *partsParser) GameState() dem.GameState {
+	pp.state.GameState = pp.Parser.GameState()
+	return &pp.state
+}
+
+func (pp *partsParser) CurrentFrame() int {
+	return pp.frameOffset + pp.Parser.CurrentFrame()
+}
+
+// Header returns the current part's header with the playback totals of all
+// parts parsed so far added in.
+func (pp *partsParser) Header() common.DemoHeader {
+	h := pp.Parser.Header()
+	h.PlaybackTime += pp.done.PlaybackTime
+	h.PlaybackTicks += pp.done.PlaybackTicks
+	h.PlaybackFrames += pp.done.PlaybackFrames
+	return h
+}
+
+func (pp *partsParser) ParseToEnd() error {
+	for i, path := range pp.paths {
+		if i > 0 {
+			fmt.Printf("🧩 Continuing with part %d: %s\n", i+1, path)
+			if err := pp.open(path); err != nil {
+				return err
+			}
+			pp.state.newPart = true
+		}
+
+		err := pp.Parser.ParseToEnd()
+		pp.file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		pp.done = pp.Header()
+		pp.frameOffset = pp.CurrentFrame()
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"math"
+	"time"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+// Round phases reported in the phase column
+const (
+	phaseFreezetime = "freezetime"
+	phaseLive       = "live"
+	phasePostPlant  = "post-plant"
+	phaseOver       = "over"
+)
+
+// Fallback timers for demos whose game rules don't carry them
+const (
+	defaultFreezeTime = 15 * time.Second
+	defaultRoundTime  = 115 * time.Second
+	defaultBombTime   = 40 * time.Second
+)
+
+var (
+	roundPhase     = phaseOver
+	phaseStartTick int
+	phaseLength    time.Duration
+
+	// roundTick counts the sampled ticks since freeze time ended, -1 before
+	roundTick       = -1
+	lastRoundTicked int
+	// liveStartTick is when freeze time ended, -1 until it has this round
+	liveStartTick = -1
+)
+
+func registerPhaseHandlers(p dem.Parser) {
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		roundTick = -1
+		liveStartTick = -1
+		setPhase(p, phaseFreezetime, ruleOrDefault(p.GameState().Rules().FreezeTime, defaultFreezeTime))
+	})
+
+	p.RegisterEventHandler(func(e events.RoundFreezetimeEnd) {
+		roundTick = 0
+		lastRoundTicked = p.GameState().IngameTick()
+		liveStartTick = lastRoundTicked
+		setPhase(p, phaseLive, ruleOrDefault(p.GameState().Rules().RoundTime, defaultRoundTime))
+	})
+
+	// The round clock is replaced by the bomb timer once C4 is down
+	p.RegisterEventHandler(func(e events.BombPlanted) {
+		setPhase(p, phasePostPlant, ruleOrDefault(p.GameState().Rules().BombTime, defaultBombTime))
+	})
+
+	p.RegisterEventHandler(func(e events.RoundEnd) {
+		setPhase(p, phaseOver, 0)
+	})
+}
+
+func setPhase(p dem.Parser, phase string, length time.Duration) {
+	roundPhase = phase
+	phaseStartTick = p.GameState().IngameTick()
+	phaseLength = length
+}
+
+func ruleOrDefault(rule func() (time.Duration, error), fallback time.Duration) time.Duration {
+	d, err := rule()
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// advanceRoundTick returns the round_tick of a sampled tick, making rounds
+// line up as sequences starting at 0 from the moment they go live.
+func advanceRoundTick(tick int) int {
+	if roundTick >= 0 && tick != lastRoundTicked {
+		roundTick++
+		lastRoundTicked = tick
+	}
+	return roundTick
+}
+
+// roundTimeSeconds formats the time since freeze time ended, blank while the
+// round isn't live yet
+func roundTimeSeconds(tick int, rate float64) string {
+	if liveStartTick < 0 {
+		return ""
+	}
+	return formatSeconds(float64(tick-liveStartTick) / rate)
+}
+
+// roundTimeRemaining returns the seconds left on the clock of the current phase
+// (freeze countdown, round timer or bomb timer), never going below zero.
+func roundTimeRemaining(tick int, rate float64) float64 {
+	if roundPhase == phaseOver {
+		return 0
+	}
+	elapsed := float64(tick-phaseStartTick) / rate
+	return math.Max(phaseLength.Seconds()-elapsed, 0)
+}
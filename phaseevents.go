@@ -0,0 +1,41 @@
+package main
+
+import (
+	"io"
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	exportPhases bool
+	phasesFile   io.WriteCloser
+	phasesWriter recordWriter
+)
+
+// registerPhaseEventHandlers writes one phases.csv row per round phase
+// change, a timeline of the same transitions the phase column is built from.
+func registerPhaseEventHandlers(p dem.Parser) {
+	phasesFile, phasesWriter = openCSV(outputPath("phases.csv"), []string{
+		"tick", "game_time_seconds", "round", "phase",
+	})
+
+	write := func(phase string) {
+		if skippingRound || currentRound == 0 {
+			return
+		}
+		tick := p.GameState().IngameTick()
+		phasesWriter.Write([]string{
+			strconv.Itoa(tick),
+			formatSeconds(gameTimeSeconds(tick, tickRate(p))),
+			strconv.Itoa(currentRound),
+			phase,
+		})
+	}
+
+	p.RegisterEventHandler(func(e events.RoundStart) { write("round_start") })
+	p.RegisterEventHandler(func(e events.RoundFreezetimeEnd) { write("freezetime_end") })
+	p.RegisterEventHandler(func(e events.BombPlanted) { write("bomb_planted") })
+	p.RegisterEventHandler(func(e events.RoundEnd) { write("round_end") })
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+var (
+	splitPlayers bool
+
+	// playerDir is where player files currently go: the output folder, or
+	// the current round's folder with -split-rounds. Empty until a round
+	// starts in split-rounds mode.
+	playerDir     string
+	playerFiles   = map[string]io.WriteCloser{}
+	playerWriters = map[string]recordWriter{}
+)
+
+// playerKey identifies a player in file names and id maps. Bots all have
+// SteamID 0, so they are told apart by name instead.
+func playerKey(player *common.Player) string {
+	if player.SteamID64 == 0 {
+		return "bot_" + player.Name
+	}
+	return fmt.Sprintf("player_%d", player.SteamID64)
+}
+
+// playerWriter returns the writer for player's file, opening it on first use
+func playerWriter(player *common.Player) recordWriter {
+	if playerDir == "" {
+		return nil
+	}
+	key := playerKey(player)
+	if pseudonyms && player.SteamID64 != 0 {
+		key = fmt.Sprintf("player_%d", pseudonymID(player.SteamID64))
+	}
+	if w, ok := playerWriters[key]; ok {
+		return w
+	}
+	file, w := openTickWriter(joinOutputPath(playerDir, key+tickFileExt()))
+	playerFiles[key] = file
+	playerWriters[key] = w
+	return w
+}
+
+// startPlayerRound closes the previous round's player files and moves on to
+// the folder of the round that just started.
+func startPlayerRound() {
+	closePlayerFiles()
+	playerDir = outputPath(fmt.Sprintf("round_%d%s", currentRound, roundOutputSuffix(currentRound)))
+	if err := prepareOutputFolder(playerDir); err != nil {
+		failOutput("creating round folder", err)
+	}
+	fmt.Printf("➡️  Started round %d → writing player files to %s\n", currentRound, playerDir)
+}
+
+func closePlayerFiles() {
+	for key, w := range playerWriters {
+		if fsyncRounds {
+			syncOutput(playerFiles[key], w)
+		}
+		closeOutput(playerFiles[key], w)
+	}
+	clear(playerFiles)
+	clear(playerWriters)
+}
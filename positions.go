@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/golang/geo/r3"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+// positionsOnly trims the tick export down to positionsHeader and skips
+// every per-row lookup beyond the position itself.
+var positionsOnly bool
+
+var positionsHeader = []string{"tick", "steamid", "pos_x", "pos_y", "pos_z"}
+
+// positionValues is the -positions-only counterpart of playerValues, nil
+// when -strict skips the row
+func positionValues(ctx tickContext, player *common.Player) []string {
+	if strict {
+		if reason := missingData(player); reason != "" {
+			skipIncompleteRow(ctx.tick, player, reason)
+			return nil
+		}
+	}
+	pos := player.Position()
+	if interpolate {
+		pos = interpolatedPose(ctx, player, poseOf(player)).pos
+	}
+	return positionRow(ctx.tick, player.SteamID64, pos)
+}
+
+// positionRow formats with strconv directly since Sprintf dominates on big
+// demos
+func positionRow(tick int, steamID uint64, pos r3.Vector) []string {
+	return []string{
+		strconv.Itoa(tick),
+		strconv.FormatUint(steamID, 10),
+		strconv.FormatFloat(pos.X, 'f', 2, 64),
+		strconv.FormatFloat(pos.Y, 'f', 2, 64),
+		strconv.FormatFloat(pos.Z, 'f', 2, 64),
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/geo/r3"
+)
+
+var benchPos = r3.Vector{X: -1234.56789, Y: 2345.6789, Z: -167.03125}
+
+func BenchmarkPositionValues(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		positionRow(i, 76561198000000001, benchPos)
+	}
+}
+
+// BenchmarkPositionValuesSprintf is the Sprintf formatting playerValues uses,
+// for comparison
+func BenchmarkPositionValuesSprintf(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = []string{
+			fmt.Sprintf("%d", i),
+			fmt.Sprintf("%d", uint64(76561198000000001)),
+			fmt.Sprintf("%.2f", benchPos.X),
+			fmt.Sprintf("%.2f", benchPos.Y),
+			fmt.Sprintf("%.2f", benchPos.Z),
+		}
+	}
+}
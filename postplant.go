@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+// postPlantOnly limits the tick export to the post-plant phase of each
+// round, from the plant until the round ends
+var (
+	postPlantOnly     bool
+	secondsSincePlant float64
+)
+
+// inPostPlant reports whether tick is after this round's plant, keeping the
+// time since the plant for the time_since_plant column
+func inPostPlant(tick int, rate float64) bool {
+	if roundPhase != phasePostPlant {
+		return false
+	}
+	secondsSincePlant = float64(tick-phaseStartTick) / rate
+	return true
+}
+
+// postPlantColumns adds time_since_plant to the rows of -post-plant-only
+type postPlantColumns struct{}
+
+func (postPlantColumns) Headers() []string {
+	return []string{"time_since_plant"}
+}
+
+func (postPlantColumns) Values(tick int, p *common.Player) []string {
+	return []string{strconv.FormatFloat(secondsSincePlant, 'f', 3, 64)}
+}
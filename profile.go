@@ -0,0 +1,67 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"syscall"
+)
+
+// startProfiling starts the -cpuprofile and sets up -memprofile. The
+// returned stop writes both out; it also runs on Ctrl-C/SIGTERM so an
+// interrupted run still leaves usable profiles.
+func startProfiling(cpuPath, memPath string) (stop func()) {
+	var cpuFile *os.File
+	if cpuPath != "" {
+		var err error
+		cpuFile, err = os.Create(cpuPath)
+		if err != nil {
+			log.Fatalf("❌ Failed to create CPU profile: %v", err)
+		}
+		if err := pprof.StartCPUProfile(cpuFile); err != nil {
+			log.Fatalf("❌ Failed to start CPU profile: %v", err)
+		}
+	}
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			if cpuFile != nil {
+				pprof.StopCPUProfile()
+				cpuFile.Close()
+			}
+			if memPath != "" {
+				writeHeapProfile(memPath)
+			}
+		})
+	}
+
+	if cpuPath != "" || memPath != "" {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sig
+			stop()
+			log.Printf("⚠️  Interrupted, profiles written")
+			os.Exit(130)
+		}()
+	}
+	return stop
+}
+
+func writeHeapProfile(path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		log.Printf("⚠️  Failed to create memory profile: %v", err)
+		return
+	}
+	defer f.Close()
+	// Get up-to-date statistics
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		log.Printf("⚠️  Failed to write memory profile: %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+// Package tickpb decodes the TickRecord stream written by democamexporter's
+// -format protobuf, without protoc or any dependency outside the standard
+// library. It follows tick.proto by hand, like tickFields does on the
+// writing side, so keep the three in sync when adding fields.
+//
+// Integer and float fields are int64 and float64 so files written with
+// -proto-int int64 or -proto-float float64 decode as well.
+package tickpb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// TickRecord is one player's state on one sampled tick, see tick.proto
+type TickRecord struct {
+	Tick                  int64
+	PlayerName            string
+	PosX                  float64
+	PosY                  float64
+	PosZ                  float64
+	ViewDirX              float64
+	ViewDirY              float64
+	IsDucking             bool
+	IsDuckingInProgress   bool
+	IsUnduckingInProgress bool
+	IsStanding            bool
+	RoundTimeRemaining    float64
+	Phase                 string
+	Alive                 bool
+	Dx                    float64
+	Dy                    float64
+	Dz                    float64
+	GameTimeSeconds       float64
+	Teleport              bool
+	PlayerID              int64
+	FlashedBy             string
+	Frame                 int64
+	SteamID               uint64
+	RoundTick             int64
+	MoneySpent            int64
+	NearestEnemyDist      float64
+	NearestEnemySteamID   uint64
+	RecoilIndex           float64
+	Yaw                   float64
+	Pitch                 float64
+	NearestEnemyAngleDiff float64
+	HasPosition           bool
+	Side                  string
+	StartingSide          string
+	AimingAtEnemy         bool
+	IsBotTakeover         bool
+	OriginalSteamID       uint64
+	AimpunchYaw           float64
+	AimpunchPitch         float64
+
+	// Columns without a dedicated field, keyed by CSV column name
+	Extra map[string]string
+}
+
+// extraFieldNum is the field of the Extra map
+const extraFieldNum = 100
+
+var errTruncated = errors.New("tickpb: truncated message")
+
+// Reader reads the length-delimited TickRecords of a tick file
+type Reader struct {
+	r   *bufio.Reader
+	buf []byte
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next returns the next record, or io.EOF after the last one
+func (rd *Reader) Next() (*TickRecord, error) {
+	size, err := binary.ReadUvarint(rd.r)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(cap(rd.buf)) < size {
+		rd.buf = make([]byte, size)
+	}
+	rd.buf = rd.buf[:size]
+	if _, err := io.ReadFull(rd.r, rd.buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	rec := &TickRecord{}
+	return rec, rec.Unmarshal(rd.buf)
+}
+
+// Unmarshal decodes one TickRecord message without its length prefix.
+// Unknown fields are skipped, so newer files still decode.
+func (r *TickRecord) Unmarshal(b []byte) error {
+	*r = TickRecord{}
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errTruncated
+		}
+		b = b[n:]
+		num := tag >> 3
+
+		switch tag & 7 {
+		case 0:
+			v, n := binary.Uvarint(b)
+			if n <= 0 {
+				return errTruncated
+			}
+			b = b[n:]
+			r.setVarint(num, v)
+		case 1:
+			if len(b) < 8 {
+				return errTruncated
+			}
+			r.setFloat(num, math.Float64frombits(binary.LittleEndian.Uint64(b)))
+			b = b[8:]
+		case 5:
+			if len(b) < 4 {
+				return errTruncated
+			}
+			r.setFloat(num, float64(math.Float32frombits(binary.LittleEndian.Uint32(b))))
+			b = b[4:]
+		case 2:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < size {
+				return errTruncated
+			}
+			data := b[n : n+int(size)]
+			b = b[n+int(size):]
+			if num == extraFieldNum {
+				if err := r.addExtra(data); err != nil {
+					return err
+				}
+				continue
+			}
+			r.setString(num, string(data))
+		default:
+			return fmt.Errorf("tickpb: field %d has unsupported wire type %d", num, tag&7)
+		}
+	}
+	return nil
+}
+
+func (r *TickRecord) setVarint(num, v uint64) {
+	switch num {
+	case 1:
+		r.Tick = int64(v)
+	case 8:
+		r.IsDucking = v != 0
+	case 9:
+		r.IsDuckingInProgress = v != 0
+	case 10:
+		r.IsUnduckingInProgress = v != 0
+	case 11:
+		r.IsStanding = v != 0
+	case 14:
+		r.Alive = v != 0
+	case 19:
+		r.Teleport = v != 0
+	case 20:
+		r.PlayerID = int64(v)
+	case 22:
+		r.Frame = int64(v)
+	case 23:
+		r.SteamID = v
+	case 24:
+		r.RoundTick = int64(v)
+	case 25:
+		r.MoneySpent = int64(v)
+	case 27:
+		r.NearestEnemySteamID = v
+	case 32:
+		r.HasPosition = v != 0
+	case 35:
+		r.AimingAtEnemy = v != 0
+	case 36:
+		r.IsBotTakeover = v != 0
+	case 37:
+		r.OriginalSteamID = v
+	}
+}
+
+func (r *TickRecord) setFloat(num uint64, v float64) {
+	switch num {
+	case 3:
+		r.PosX = v
+	case 4:
+		r.PosY = v
+	case 5:
+		r.PosZ = v
+	case 6:
+		r.ViewDirX = v
+	case 7:
+		r.ViewDirY = v
+	case 12:
+		r.RoundTimeRemaining = v
+	case 15:
+		r.Dx = v
+	case 16:
+		r.Dy = v
+	case 17:
+		r.Dz = v
+	case 18:
+		r.GameTimeSeconds = v
+	case 26:
+		r.NearestEnemyDist = v
+	case 28:
+		r.RecoilIndex = v
+	case 29:
+		r.Yaw = v
+	case 30:
+		r.Pitch = v
+	case 31:
+		r.NearestEnemyAngleDiff = v
+	case 38:
+		r.AimpunchYaw = v
+	case 39:
+		r.AimpunchPitch = v
+	}
+}
+
+func (r *TickRecord) setString(num uint64, v string) {
+	switch num {
+	case 2:
+		r.PlayerName = v
+	case 13:
+		r.Phase = v
+	case 21:
+		r.FlashedBy = v
+	case 33:
+		r.Side = v
+	case 34:
+		r.StartingSide = v
+	}
+}
+
+// addExtra decodes one map entry, a message with the key as field 1 and the
+// value as field 2
+func (r *TickRecord) addExtra(b []byte) error {
+	var key, value string
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 || tag&7 != 2 {
+			return errTruncated
+		}
+		b = b[n:]
+		size, n := binary.Uvarint(b)
+		if n <= 0 || uint64(len(b)-n) < size {
+			return errTruncated
+		}
+		s := string(b[n : n+int(size)])
+		b = b[n+int(size):]
+		switch tag >> 3 {
+		case 1:
+			key = s
+		case 2:
+			value = s
+		}
+	}
+	if r.Extra == nil {
+		r.Extra = map[string]string{}
+	}
+	r.Extra[key] = value
+	return nil
+}
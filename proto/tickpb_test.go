@@ -0,0 +1,40 @@
+package tickpb
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Records as democamexporter's protoWriter writes them, see
+// TestProtoWriter in protobuf_test.go
+var records = map[string]string{
+	"int32/float32": "=\b\xd2\t\x12\x05Alice\x1d\x00\x00H\xc1p\x01\xb8\x01\x81\x98\xf9\x92\x90\x80\x80\x88\x01\xc0\x01\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01\xa2\x06\x12\n\blocation\x12\x06A Site",
+	"int64/float64": "A\b\xd2\t\x12\x05Alice\x19\x00\x00\x00\x00\x00\x00)\xc0p\x01\xb8\x01\x81\x98\xf9\x92\x90\x80\x80\x88\x01\xc0\x01\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01\xa2\x06\x12\n\blocation\x12\x06A Site",
+}
+
+func TestReader(t *testing.T) {
+	for name, data := range records {
+		rd := NewReader(strings.NewReader(data))
+		rec, err := rd.Next()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if rec.Tick != 1234 || rec.PlayerName != "Alice" || rec.PosX != -12.5 || !rec.Alive ||
+			rec.SteamID != 76561198000000001 || rec.RoundTick != -1 || rec.Extra["location"] != "A Site" {
+			t.Errorf("%s: decoded %+v", name, rec)
+		}
+		if _, err := rd.Next(); !errors.Is(err, io.EOF) {
+			t.Errorf("%s: after the last record got %v, want io.EOF", name, err)
+		}
+	}
+}
+
+func TestReaderTruncated(t *testing.T) {
+	data := records["int32/float32"]
+	_, err := NewReader(strings.NewReader(data[:len(data)-3])).Next()
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("got %v, want io.ErrUnexpectedEOF", err)
+	}
+}
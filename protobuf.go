@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Wire types from the protobuf encoding spec
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+type protoKind int
+
+const (
+	kindInt protoKind = iota
+	// kindUint is for the uint64 SteamID fields, which -proto-int leaves alone
+	kindUint
+	kindFloat
+	kindBool
+	kindString
+)
+
+type protoField struct {
+	num  uint64
+	kind protoKind
+}
+
+// extraFieldNum is the TickRecord map holding columns without their own field
+const extraFieldNum = 100
+
+// tickFields maps tick columns to their field in proto/tick.proto. Keep it,
+// tick.proto and the tickpb decoder in sync when adding columns; anything
+// missing here ends up in extra.
+var tickFields = map[string]protoField{
+	"tick":                     {1, kindInt},
+	"player_name":              {2, kindString},
+	"pos_x":                    {3, kindFloat},
+	"pos_y":                    {4, kindFloat},
+	"pos_z":                    {5, kindFloat},
+	"view_dir_x":               {6, kindFloat},
+	"view_dir_y":               {7, kindFloat},
+	"is_ducking":               {8, kindBool},
+	"is_ducking_in_progress":   {9, kindBool},
+	"is_unducking_in_progress": {10, kindBool},
+	"is_standing":              {11, kindBool},
+	"round_time_remaining":     {12, kindFloat},
+	"phase":                    {13, kindString},
+	"alive":                    {14, kindBool},
+	"dx":                       {15, kindFloat},
+	"dy":                       {16, kindFloat},
+	"dz":                       {17, kindFloat},
+	"game_time_seconds":        {18, kindFloat},
+	"teleport":                 {19, kindBool},
+	"player_id":                {20, kindInt},
+	"flashed_by":               {21, kindString},
+	"frame":                    {22, kindInt},
+	"steamid":                  {23, kindUint},
+	"round_tick":               {24, kindInt},
+	"money_spent":              {25, kindInt},
+	"nearest_enemy_dist":       {26, kindFloat},
+	"nearest_enemy_steamid":    {27, kindUint},
+	"recoil_index":             {28, kindFloat},
+	"yaw":                      {29, kindFloat},
+	"pitch":                    {30, kindFloat},
+	"nearest_enemy_angle_diff": {31, kindFloat},
+	"has_position":             {32, kindBool},
+	"side":                     {33, kindString},
+	"starting_side":            {34, kindString},
+	"aiming_at_enemy":          {35, kindBool},
+	"is_bot_takeover":          {36, kindBool},
+	"original_steamid":         {37, kindUint},
+	"aimpunch_yaw":             {38, kindFloat},
+	"aimpunch_pitch":           {39, kindFloat},
+}
+
+// -proto-int and -proto-float pick the width of the int32 and float fields,
+// for readers that compiled tick.proto with int64 / double instead. Varints
+// are the same on the wire for both int widths, but int32 values are
+// checked so they can't overflow silently.
+var (
+	protoInt   = "int32"
+	protoFloat = "float32"
+)
+
+// protoWriter encodes rows as length-delimited TickRecord messages
+type protoWriter struct {
+	w      *bufio.Writer
+	header []string
+	msg    []byte
+	frame  []byte
+}
+
+func newProtoWriter(w io.Writer, header []string) *protoWriter {
+	return &protoWriter{w: bufio.NewWriter(w), header: header}
+}
+
+// Write encodes one row. Empty values are left out, which proto3 readers see
+// as the field's zero value.
+func (pw *protoWriter) Write(record []string) error {
+	msg := pw.msg[:0]
+	for i, value := range record {
+		if value == "" || i >= len(pw.header) {
+			continue
+		}
+		field, ok := tickFields[pw.header[i]]
+		if !ok {
+			msg = appendExtra(msg, pw.header[i], value)
+			continue
+		}
+
+		switch field.kind {
+		case kindInt:
+			bits := 64
+			if protoInt == "int32" {
+				bits = 32
+			}
+			n, err := strconv.ParseInt(value, 10, bits)
+			if err != nil {
+				return fmt.Errorf("%s: %w", pw.header[i], err)
+			}
+			msg = appendTag(msg, field.num, wireVarint)
+			msg = binary.AppendUvarint(msg, uint64(n))
+		case kindUint:
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return err
+			}
+			msg = appendTag(msg, field.num, wireVarint)
+			msg = binary.AppendUvarint(msg, n)
+		case kindFloat:
+			if protoFloat == "float64" {
+				f, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return err
+				}
+				msg = appendTag(msg, field.num, wireFixed64)
+				msg = binary.LittleEndian.AppendUint64(msg, math.Float64bits(f))
+				continue
+			}
+			f, err := strconv.ParseFloat(value, 32)
+			if err != nil {
+				return fmt.Errorf("%s: %w", pw.header[i], err)
+			}
+			msg = appendTag(msg, field.num, wireFixed32)
+			msg = binary.LittleEndian.AppendUint32(msg, math.Float32bits(float32(f)))
+		case kindBool:
+			if value != "1" && value != "true" {
+				continue
+			}
+			msg = appendTag(msg, field.num, wireVarint)
+			msg = binary.AppendUvarint(msg, 1)
+		case kindString:
+			msg = appendString(msg, field.num, value)
+		}
+	}
+	pw.msg = msg
+
+	pw.frame = binary.AppendUvarint(pw.frame[:0], uint64(len(msg)))
+	if _, err := pw.w.Write(pw.frame); err != nil {
+		return err
+	}
+	_, err := pw.w.Write(msg)
+	return err
+}
+
+func (pw *protoWriter) Flush() {
+	pw.w.Flush()
+}
+
+func appendTag(b []byte, num uint64, wireType uint64) []byte {
+	return binary.AppendUvarint(b, num<<3|wireType)
+}
+
+func appendString(b []byte, num uint64, s string) []byte {
+	b = appendTag(b, num, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// appendExtra encodes one entry of the extra map, which on the wire is a
+// nested message with the key as field 1 and the value as field 2.
+func appendExtra(b []byte, key, value string) []byte {
+	entry := appendString(nil, 1, key)
+	entry = appendString(entry, 2, value)
+	b = appendTag(b, extraFieldNum, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(entry)))
+	return append(b, entry...)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+// The same records are decoded in proto/tickpb_test.go, which pins the
+// writer and the tickpb decoder to one wire format
+var (
+	protoTestHeader = []string{"tick", "player_name", "pos_x", "alive", "steamid", "round_tick", "location"}
+	protoTestRow    = []string{"1234", "Alice", "-12.5", "1", "76561198000000001", "-1", "A Site"}
+)
+
+func TestProtoWriter(t *testing.T) {
+	tests := []struct {
+		ints, floats string
+		want         string
+	}{
+		{"int32", "float32", "=\b\xd2\t\x12\x05Alice\x1d\x00\x00H\xc1p\x01\xb8\x01\x81\x98\xf9\x92\x90\x80\x80\x88\x01\xc0\x01\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01\xa2\x06\x12\n\blocation\x12\x06A Site"},
+		{"int64", "float64", "A\b\xd2\t\x12\x05Alice\x19\x00\x00\x00\x00\x00\x00)\xc0p\x01\xb8\x01\x81\x98\xf9\x92\x90\x80\x80\x88\x01\xc0\x01\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01\xa2\x06\x12\n\blocation\x12\x06A Site"},
+	}
+	t.Cleanup(func() { protoInt, protoFloat = "int32", "float32" })
+
+	for _, tt := range tests {
+		protoInt, protoFloat = tt.ints, tt.floats
+		var buf bytes.Buffer
+		pw := newProtoWriter(&buf, protoTestHeader)
+		if err := pw.Write(protoTestRow); err != nil {
+			t.Fatal(err)
+		}
+		pw.Flush()
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s/%s: wrote %q, want %q", tt.ints, tt.floats, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+// -pseudonyms replaces every player's name and SteamID in the tick export
+// with random stand-ins, drawn anew for each demo so players (and so teams)
+// can't be recognized across demos. The mapping goes to -pseudonym-map,
+// which should be kept apart from the shared dataset. Bots keep their names.
+var (
+	pseudonyms     bool
+	pseudonymRand  *rand.Rand
+	pseudonymIDs   = map[uint64]uint64{}
+	pseudonymNames = map[uint64]string{}
+	// realNames are the names last seen for each SteamID, for the mapping
+	realNames     = map[uint64]string{}
+	pseudonymFile io.WriteCloser
+	pseudonymMap  recordWriter
+	// pseudonymCols are the tick columns holding a SteamID
+	pseudonymCols = map[string]bool{
+		"steamid": true, "nearest_enemy_steamid": true, "original_steamid": true,
+	}
+)
+
+// setupPseudonyms checks the flags that would leak identities past the
+// pseudonyms and opens the mapping file. A seed of 0 picks a random one.
+func setupPseudonyms(fs *flag.FlagSet, mapPath string, seed int64) error {
+	if mapPath == "" {
+		return fmt.Errorf("-pseudonyms needs -pseudonym-map to write the mapping to")
+	}
+	// Everything but the tick export names players as they are
+	leaky := append([]string{
+		"compact-names", "roster", "text-log", "round-window", "leaderboard",
+		"visibility", "spotted", "reactions", "summary-only", "expected-steamids",
+		"round-summary-json",
+	}, eventFlags...)
+	given := givenFlags(fs)
+	for _, name := range leaky {
+		if !given[name] {
+			continue
+		}
+		if v := fs.Lookup(name).Value.String(); v != "false" && v != "0" && v != "" {
+			return fmt.Errorf("-pseudonyms can't be combined with -%s, which writes real player names or SteamIDs", name)
+		}
+	}
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	pseudonymRand = rand.New(rand.NewSource(seed))
+	pseudonymFile, pseudonymMap = createCSV(mapPath, []string{
+		"demo", "pseudonym_steamid", "pseudonym_name", "steamid", "player_name",
+	})
+	return nil
+}
+
+// pseudonymID returns the current demo's stand-in for steamID, 0 for bots
+func pseudonymID(steamID uint64) uint64 {
+	if steamID == 0 {
+		return 0
+	}
+	id, ok := pseudonymIDs[steamID]
+	if !ok {
+		// 53 bits so the ids survive tools that read numbers as doubles
+		for id == 0 || pseudonymNames[id] != "" {
+			id = pseudonymRand.Uint64() >> 11
+		}
+		pseudonymIDs[steamID] = id
+		// The whole id, so names are as unique as the ids
+		pseudonymNames[id] = fmt.Sprintf("anon_%014x", id)
+	}
+	return id
+}
+
+// pseudonymizeRow swaps the player_name, flashed_by and SteamID columns of a
+// tick row
+func pseudonymizeRow(header, row []string, player *common.Player) {
+	for i, col := range header {
+		switch {
+		case col == "player_name" && player.SteamID64 != 0:
+			realNames[player.SteamID64] = player.Name
+			row[i] = pseudonymNames[pseudonymID(player.SteamID64)]
+		case col == "flashed_by" && row[i] != "":
+			if flasher := flashedBy[playerKey(player)]; flasher != nil && flasher.SteamID64 != 0 {
+				realNames[flasher.SteamID64] = flasher.Name
+				row[i] = pseudonymNames[pseudonymID(flasher.SteamID64)]
+			}
+		case pseudonymCols[col] && row[i] != "" && row[i] != "0":
+			steamID, err := strconv.ParseUint(row[i], 10, 64)
+			if err == nil {
+				row[i] = strconv.FormatUint(pseudonymID(steamID), 10)
+			}
+		}
+	}
+}
+
+// writePseudonymMap adds the demo's pseudonyms to -pseudonym-map
+func writePseudonymMap() {
+	steamIDs := make([]uint64, 0, len(pseudonymIDs))
+	for steamID := range pseudonymIDs {
+		steamIDs = append(steamIDs, steamID)
+	}
+	sort.Slice(steamIDs, func(i, j int) bool { return steamIDs[i] < steamIDs[j] })
+
+	for _, steamID := range steamIDs {
+		id := pseudonymIDs[steamID]
+		pseudonymMap.Write([]string{
+			demoName,
+			strconv.FormatUint(id, 10),
+			pseudonymNames[id],
+			strconv.FormatUint(steamID, 10),
+			realNames[steamID],
+		})
+	}
+}
+
+// resetPseudonyms draws new pseudonyms for the next demo
+func resetPseudonyms() {
+	clear(pseudonymIDs)
+	clear(pseudonymNames)
+	clear(realNames)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+func TestPseudonymizeRowFlashedBy(t *testing.T) {
+	pseudonymRand = rand.New(rand.NewSource(1))
+	t.Cleanup(func() {
+		resetPseudonyms()
+		clear(flashedBy)
+	})
+
+	victim := &common.Player{Name: "Alice", SteamID64: 76561198000000001}
+	flasher := &common.Player{Name: "Bob", SteamID64: 76561198000000002}
+	flashedBy[playerKey(victim)] = flasher
+
+	header := []string{"tick", "player_name", "flashed_by", "steamid", "nearest_enemy_steamid"}
+	row := []string{"100", "Alice", "Bob", "76561198000000001", "76561198000000002"}
+	pseudonymizeRow(header, row, victim)
+
+	for i, value := range row {
+		for _, real := range []string{"Alice", "Bob", "76561198000000001", "76561198000000002"} {
+			if strings.Contains(value, real) {
+				t.Errorf("%s = %q still holds %q", header[i], value, real)
+			}
+		}
+	}
+	if want := pseudonymNames[pseudonymID(flasher.SteamID64)]; row[2] != want {
+		t.Errorf("flashed_by = %q, want the flasher's pseudonym %q", row[2], want)
+	}
+}
+
+func TestPseudonymNamesUnique(t *testing.T) {
+	pseudonymRand = rand.New(rand.NewSource(1))
+	t.Cleanup(resetPseudonyms)
+
+	seen := map[string]uint64{}
+	for steamID := uint64(1); steamID <= 20000; steamID++ {
+		name := pseudonymNames[pseudonymID(steamID)]
+		if other, ok := seen[name]; ok {
+			t.Fatalf("SteamIDs %d and %d both got %q", other, steamID, name)
+		}
+		seen[name] = steamID
+	}
+}
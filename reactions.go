@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+// First contact is the first tick of a live round on which an enemy counts
+// as seen after not being seen on the tick before. -reaction-contact picks
+// what "seen" means:
+//
+//	fov      inside -visibility-fov and -visibility-max-dist, walls ignored
+//	spotted  the game's spotted-by state, which accounts for walls and smokes
+//	both     both of the above
+//
+// A contact is answered by the player's next shot within -reaction-window-ms,
+// as long as the enemy stayed seen until then. Contacts that end without a
+// shot, or only get one after the window, don't get a row.
+var (
+	exportReactions bool
+	reactionContact string
+	reactionWindow  float64
+	reactionsFile   io.WriteCloser
+	reactionsWriter recordWriter
+
+	// Pairs seen on the last frame, and the unanswered contacts among them
+	inContact       = map[[2]*common.Player]bool{}
+	pendingContacts = map[[2]*common.Player]int{}
+)
+
+func registerReactionHandlers(p dem.Parser) {
+	reactionsFile, reactionsWriter = openCSV(outputPath("reactions.csv"), []string{
+		"round", "shooter", "enemy", "contact_tick", "fire_tick", "game_time_seconds",
+		"reaction_ticks", "reaction_ms", "weapon",
+	})
+
+	p.RegisterEventHandler(func(events.RoundStart) {
+		clear(inContact)
+		clear(pendingContacts)
+	})
+
+	p.RegisterEventHandler(func(events.FrameDone) {
+		if !reactionsLive() || (roundPhase != phaseLive && roundPhase != phasePostPlant) {
+			return
+		}
+		trackContacts(p)
+	})
+
+	p.RegisterEventHandler(func(e events.WeaponFire) {
+		if e.Shooter == nil || !reactionsLive() {
+			return
+		}
+		answerContact(p, e.Shooter, e.Weapon)
+	})
+}
+
+// reactionsLive reports whether the frame belongs to a real round of the
+// match, not warmup, a skipped knife round or what's left after the match end
+func reactionsLive() bool {
+	return !skippingRound && !matchEnded && currentRound > 0
+}
+
+// trackContacts runs on every frame, not just sampled ticks, so contact
+// ticks are exact
+func trackContacts(p dem.Parser) {
+	tick := p.GameState().IngameTick()
+	window := reactionWindow / 1000 * tickRate(p)
+	players := p.GameState().Participants().Playing()
+	for _, observer := range players {
+		for _, target := range players {
+			if !isEnemy(observer, target) {
+				continue
+			}
+			pair := [2]*common.Player{observer, target}
+			seen := observer.IsAlive() && target.IsAlive() && contactSeen(observer, target)
+			switch {
+			case !seen:
+				delete(pendingContacts, pair)
+			case !inContact[pair]:
+				pendingContacts[pair] = tick
+			case float64(tick-pendingContacts[pair]) > window:
+				delete(pendingContacts, pair)
+			}
+			inContact[pair] = seen
+		}
+	}
+}
+
+func contactSeen(observer, target *common.Player) bool {
+	switch reactionContact {
+	case "spotted":
+		return target.IsSpottedBy(observer)
+	case "both":
+		return target.IsSpottedBy(observer) && inView(observer, target)
+	}
+	return inView(observer, target)
+}
+
+// answerContact writes the shooter's earliest open contact and closes all of
+// them; the enemies have to leave view and come back for a new one
+func answerContact(p dem.Parser, shooter *common.Player, weapon *common.Equipment) {
+	var first [2]*common.Player
+	contactTick := -1
+	for pair, tick := range pendingContacts {
+		if pair[0] != shooter {
+			continue
+		}
+		if contactTick < 0 || tick < contactTick {
+			first, contactTick = pair, tick
+		}
+		delete(pendingContacts, pair)
+	}
+	if contactTick < 0 {
+		return
+	}
+
+	fireTick := p.GameState().IngameTick()
+	var weaponName string
+	if weapon != nil {
+		weaponName = weapon.String()
+	}
+	reactionsWriter.Write([]string{
+		strconv.Itoa(currentRound),
+		strconv.FormatUint(shooter.SteamID64, 10),
+		strconv.FormatUint(first[1].SteamID64, 10),
+		strconv.Itoa(contactTick),
+		strconv.Itoa(fireTick),
+		formatSeconds(gameTimeSeconds(fireTick, tickRate(p))),
+		strconv.Itoa(fireTick - contactTick),
+		fmt.Sprintf("%.1f", float64(fireTick-contactTick)/tickRate(p)*1000),
+		weaponName,
+	})
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+// sprayResetSeconds is how long a player has to stop firing before the shot
+// counter used as a recoil proxy starts over
+const sprayResetSeconds = 0.5
+
+type spray struct {
+	shots    int
+	lastShot int
+}
+
+var sprays = map[string]spray{}
+
+// registerRecoilHandlers counts consecutive shots, the recoil_index fallback
+// for weapons whose entity doesn't carry the game's own recoil index.
+func registerRecoilHandlers(p dem.Parser) {
+	p.RegisterEventHandler(func(e events.WeaponFire) {
+		if e.Shooter == nil {
+			return
+		}
+		tick := p.GameState().IngameTick()
+		key := playerKey(e.Shooter)
+		s := sprays[key]
+		if float64(tick-s.lastShot) > sprayResetSeconds*tickRate(p) {
+			s.shots = 0
+		}
+		s.shots++
+		s.lastShot = tick
+		sprays[key] = s
+	})
+}
+
+// recoilIndex returns the active weapon's m_flRecoilIndex, or the number of
+// shots in the current spray when the property isn't there.
+func recoilIndex(ctx tickContext, player *common.Player) string {
+	if weapon := player.ActiveWeapon(); weapon != nil && weapon.Entity != nil {
+		if v, ok := weapon.Entity.PropertyValue("m_flRecoilIndex"); ok {
+			return fmt.Sprintf("%.2f", v.Float())
+		}
+	}
+
+	s := sprays[playerKey(player)]
+	if float64(ctx.tick-s.lastShot) > sprayResetSeconds*ctx.rate {
+		return "0"
+	}
+	return strconv.Itoa(s.shots)
+}
+
+// aimPunch returns the pawn's m_aimPunchAngle as yaw and pitch degrees, the
+// recoil kick the game adds on top of the view angle. Subtracting it from
+// the view direction leaves where the player meant to aim. Zeros when the
+// demo doesn't carry the property.
+func aimPunch(player *common.Player) (string, string) {
+	var yaw, pitch float64
+	if pawn := player.PlayerPawnEntity(); pawn != nil {
+		if v, ok := pawn.PropertyValue("m_aimPunchAngle"); ok {
+			// QAngle is pitch, yaw, roll
+			angle := v.R3Vec()
+			pitch, yaw = angle.X, angle.Y
+		}
+	}
+	return fmt.Sprintf("%.4f", yaw), fmt.Sprintf("%.4f", pitch)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	// roundInProgress is set from a counted RoundStart until its RoundEnd
+	roundInProgress bool
+	// roundStartPlayed is the game's rounds played count when it started
+	roundStartPlayed int
+	// roundOutputs counts how often each round's output was started
+	roundOutputs = map[int]int{}
+)
+
+func registerRestartHandlers(p dem.Parser) {
+	p.RegisterEventHandler(func(e events.RoundEnd) {
+		roundInProgress = false
+	})
+}
+
+func markRoundStart(gs dem.GameState) {
+	roundInProgress = true
+	roundStartPlayed = gs.TotalRoundsPlayed()
+}
+
+// nextRoundNumber numbers a new round. Normally that's the next one, but
+// when the game's rounds played count goes backwards (a backup restore) the
+// round replays an earlier number, so exports stay tied to the game's rounds.
+func nextRoundNumber(gs dem.GameState) int {
+	played := gs.TotalRoundsPlayed()
+	if currentRound > 0 && played < roundStartPlayed {
+		return max(currentRound+played-roundStartPlayed, 1)
+	}
+	return currentRound + 1
+}
+
+// roundOutputSuffix returns "" the first time a round's output is created
+// and "b", "c", ... for replays, so they don't overwrite it
+func roundOutputSuffix(round int) string {
+	n := roundOutputs[round]
+	roundOutputs[round]++
+	if n == 0 {
+		return ""
+	}
+	return string(rune('a' + n))
+}
+
+// isRoundRestart reports whether a RoundStart restarts the round in progress,
+// as after a tech pause: the previous start never ended and the game hasn't
+// counted a round since. Demos that drop a RoundEnd still move on since the
+// game's own count goes up.
+func isRoundRestart(gs dem.GameState) bool {
+	return roundInProgress && gs.TotalRoundsPlayed() == roundStartPlayed
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+// rosterColumns joins an external roster CSV into the tick export. The roster
+// needs a steamid column; all of its other columns are added as they are.
+type rosterColumns struct {
+	header []string
+	rows   map[uint64][]string
+	blank  []string
+}
+
+func loadRoster(path string) (*rosterColumns, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s is empty", path)
+	}
+
+	key := -1
+	r := &rosterColumns{rows: map[uint64][]string{}}
+	for i, name := range records[0] {
+		if name == "steamid" {
+			key = i
+			continue
+		}
+		r.header = append(r.header, name)
+	}
+	if key < 0 {
+		return nil, fmt.Errorf("%s has no steamid column", path)
+	}
+	r.blank = make([]string, len(r.header))
+
+	for line, record := range records[1:] {
+		steamID, err := strconv.ParseUint(record[key], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid steamid %q", line+2, record[key])
+		}
+		values := make([]string, 0, len(r.header))
+		values = append(values, record[:key]...)
+		values = append(values, record[key+1:]...)
+		r.rows[steamID] = values
+	}
+	return r, nil
+}
+
+func (r *rosterColumns) Headers() []string {
+	return r.header
+}
+
+// Values returns the player's roster entry, blank for unknown SteamIDs
+func (r *rosterColumns) Values(tick int, p *common.Player) []string {
+	if values, ok := r.rows[p.SteamID64]; ok {
+		return values
+	}
+	return r.blank
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxFileSize rotates the single tick file to numbered parts once it would
+// grow past this many bytes; 0 disables rotation
+var maxFileSize int64
+
+// parseSize parses a byte count with an optional K, M or G suffix
+func parseSize(s string) (int64, error) {
+	mult := int64(1)
+	switch {
+	case strings.HasSuffix(strings.ToUpper(s), "K"):
+		mult = 1 << 10
+	case strings.HasSuffix(strings.ToUpper(s), "M"):
+		mult = 1 << 20
+	case strings.HasSuffix(strings.ToUpper(s), "G"):
+		mult = 1 << 30
+	}
+	if mult > 1 {
+		s = s[:len(s)-1]
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * mult, nil
+}
+
+// partPath names rotated part n of path, e.g. all_ticks.002.csv
+func partPath(path string, n int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s.%03d%s", strings.TrimSuffix(path, ext), n, ext)
+}
+
+// rotatingFile is the file behind a rotating tick writer. It counts what is
+// written to the current part and can move on to the next one.
+type rotatingFile struct {
+	path    string
+	part    int
+	file    io.WriteCloser
+	buf     *bufio.Writer
+	written int64
+}
+
+func (f *rotatingFile) open(path string) {
+	file := openOutput(path)
+	f.file, f.buf, f.written = file, bufio.NewWriter(file), 0
+}
+
+func (f *rotatingFile) Write(b []byte) (int, error) {
+	n, err := f.buf.Write(b)
+	f.written += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) Close() error {
+	if err := f.buf.Flush(); err != nil {
+		return err
+	}
+	return f.file.Close()
+}
+
+func (f *rotatingFile) next() {
+	if err := f.Close(); err != nil {
+		failOutput("finalizing", err)
+	}
+	f.part++
+	f.open(partPath(f.path, f.part))
+	fmt.Printf("✂️  Tick file reached -max-file-size, continuing in %s\n", partPath(f.path, f.part))
+}
+
+// rotatingWriter moves its rows to a new part, with a fresh header, before
+// a row would push the current one past maxFileSize. Rows are never split.
+type rotatingWriter struct {
+	inner  recordWriter
+	file   *rotatingFile
+	header []string
+	// headerSize is what a part holds before its first row
+	headerSize int64
+}
+
+// openRotatingTickWriter is openTickWriter for -max-file-size
+func openRotatingTickWriter(path string) (io.WriteCloser, recordWriter) {
+	f := &rotatingFile{path: path}
+	f.open(path)
+
+	w := &rotatingWriter{file: f}
+	if outputFormat == "protobuf" {
+		w.inner = newProtoWriter(f, tickHeader)
+	} else {
+		w.inner = csv.NewWriter(f)
+		if nullToken != "" {
+			w.inner = &nullWriter{recordWriter: w.inner}
+		}
+		w.header = tickHeader
+		w.writeHeader()
+	}
+	return f, trackWriter(w)
+}
+
+func (w *rotatingWriter) writeHeader() {
+	if w.header != nil {
+		w.inner.Write(w.header)
+		w.inner.Flush()
+	}
+	w.headerSize = w.file.written
+}
+
+func (w *rotatingWriter) Write(record []string) error {
+	// Unquoted size of the row, close enough for both formats
+	size := int64(len(record))
+	for _, v := range record {
+		size += int64(len(v))
+	}
+	if w.file.written+size > maxFileSize && w.file.written > w.headerSize {
+		w.file.next()
+		w.writeHeader()
+	}
+
+	err := w.inner.Write(record)
+	// Push the row through to the counted file, the part's buffer keeps
+	// this cheap
+	w.inner.Flush()
+	return err
+}
+
+func (w *rotatingWriter) Flush() {
+	w.inner.Flush()
+	w.file.buf.Flush()
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"io"
+	"log"
+	"math"
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+// roundRecord accumulates everything written to a round's rounds.csv row
+type roundRecord struct {
+	number    int
+	startTick int
+	endTick   int
+	winner    common.Team
+
+	// Team scores once the round is over
+	ctScore int
+	tScore  int
+
+	// Clan names of the rosters on each side, taken at freeze-time end
+	ctTeamName string
+	tTeamName  string
+
+	ctGrenades grenadeCounts
+	tGrenades  grenadeCounts
+
+	ctEconomy teamEconomy
+	tEconomy  teamEconomy
+
+	clutch clutch
+
+	// momentum is the winning side's streak after this round, positive for
+	// CT and negative for T
+	momentum int
+
+	// What the tick export actually got for the round
+	sampledTicks int
+	lastSampled  int
+	rows         int
+	rate         float64
+}
+
+// tickCountTolerance is how far the sampled ticks of a round may stray from
+// its duration before a warning is printed
+const tickCountTolerance = 0.1
+
+// teamEconomy describes how a side went into the round: the equipment its
+// survivors brought over from the previous round and what it bought on top.
+type teamEconomy struct {
+	carried int
+	bought  int
+	kept    int
+}
+
+// decision classifies the buy as "saved" when the previous round's surviving
+// equipment made it into this round and outweighed the new purchases, or
+// "reset" when the side had to rebuy. Halftime wipes the carried gear, which
+// shows up as it not being kept.
+func (e teamEconomy) decision() string {
+	if e.carried > 0 && e.kept*2 >= e.carried && e.carried > e.bought {
+		return "saved"
+	}
+	return "reset"
+}
+
+func (e teamEconomy) columns() []string {
+	return []string{strconv.Itoa(e.carried), strconv.Itoa(e.bought), e.decision()}
+}
+
+// grenadeCounts is how many grenades of each type a side threw in a round.
+// Incendiaries count as molotovs.
+type grenadeCounts struct {
+	he, flash, smoke, molotov, decoy int
+}
+
+func (c *grenadeCounts) add(t common.EquipmentType) {
+	switch t {
+	case common.EqHE:
+		c.he++
+	case common.EqFlash:
+		c.flash++
+	case common.EqSmoke:
+		c.smoke++
+	case common.EqMolotov, common.EqIncendiary:
+		c.molotov++
+	case common.EqDecoy:
+		c.decoy++
+	}
+}
+
+func (c grenadeCounts) columns() []string {
+	return []string{
+		strconv.Itoa(c.he),
+		strconv.Itoa(c.flash),
+		strconv.Itoa(c.smoke),
+		strconv.Itoa(c.molotov),
+		strconv.Itoa(c.decoy),
+	}
+}
+
+var (
+	roundsFile   io.WriteCloser
+	roundsWriter recordWriter
+	round        roundRecord
+
+	// survivingValue is the equipment value alive at the end of the last
+	// round, by team ID so it follows the team across the side switch
+	survivingValue = map[int]int{}
+
+	// winStreak is the current momentum, see roundRecord.momentum
+	winStreak int
+)
+
+// registerRoundHandlers aggregates each round for rounds.csv and/or
+// rounds.json, depending on which of the two were asked for.
+func registerRoundHandlers(p dem.Parser) {
+	if exportRounds {
+		roundsFile, roundsWriter = openCSV(outputPath("rounds.csv"), []string{
+			"round", "start_tick", "end_tick", "start_seconds", "end_seconds", "winner",
+			"ct_team_name", "t_team_name",
+			"sampled_ticks", "expected_ticks", "tick_rows",
+			"ct_he_thrown", "ct_flash_thrown", "ct_smoke_thrown", "ct_molotov_thrown", "ct_decoy_thrown",
+			"t_he_thrown", "t_flash_thrown", "t_smoke_thrown", "t_molotov_thrown", "t_decoy_thrown",
+			"ct_carried_value", "ct_buy_value", "ct_economy",
+			"t_carried_value", "t_buy_value", "t_economy",
+			"clutch_start_tick", "clutch_start_seconds", "clutch_steamid", "clutch_opponents",
+			"momentum",
+		})
+	}
+
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		round = roundRecord{number: currentRound, startTick: p.GameState().IngameTick()}
+	})
+
+	p.RegisterEventHandler(func(e events.RoundFreezetimeEnd) {
+		gs := p.GameState()
+		round.ctTeamName = clanName(gs.TeamCounterTerrorists())
+		round.tTeamName = clanName(gs.TeamTerrorists())
+		round.ctEconomy = economyOf(gs.TeamCounterTerrorists())
+		round.tEconomy = economyOf(gs.TeamTerrorists())
+	})
+
+	p.RegisterEventHandler(func(e events.GrenadeProjectileThrow) {
+		g := e.Projectile
+		if g == nil || g.Thrower == nil || g.WeaponInstance == nil {
+			return
+		}
+		switch g.Thrower.Team {
+		case common.TeamCounterTerrorists:
+			round.ctGrenades.add(g.WeaponInstance.Type)
+		case common.TeamTerrorists:
+			round.tGrenades.add(g.WeaponInstance.Type)
+		}
+	})
+
+	p.RegisterEventHandler(func(e events.FrameDone) {
+		trackClutch(p)
+	})
+
+	p.RegisterEventHandler(func(e events.RoundEnd) {
+		gs := p.GameState()
+		recordSurvivors(gs.TeamCounterTerrorists())
+		recordSurvivors(gs.TeamTerrorists())
+
+		// Nothing to report for the knife round or warmup
+		if skippingRound || currentRound == 0 {
+			return
+		}
+		round.endTick = p.GameState().IngameTick()
+		round.winner = e.Winner
+		round.rate = tickRate(p)
+		checkTickCount(round)
+		round.ctScore = teamScore(gs.TeamCounterTerrorists())
+		round.tScore = teamScore(gs.TeamTerrorists())
+		winStreak = nextStreak(winStreak, e.Winner)
+		round.momentum = winStreak
+		writeRoundRecord(round)
+	})
+}
+
+// nextStreak extends streak if winner is the side already on it and starts
+// a new one otherwise
+func nextStreak(streak int, winner common.Team) int {
+	switch winner {
+	case common.TeamCounterTerrorists:
+		return max(streak, 0) + 1
+	case common.TeamTerrorists:
+		return min(streak, 0) - 1
+	}
+	return 0
+}
+
+// formatStreak writes CT streaks with a plus sign, e.g. +3
+func formatStreak(streak int) string {
+	if streak > 0 {
+		return "+" + strconv.Itoa(streak)
+	}
+	return strconv.Itoa(streak)
+}
+
+// countSample notes a sampled tick for the round's tick count check.
+// Frames repeating a tick (-ignore-duplicate-ticks) only count once.
+func countSample(tick int) {
+	if round.sampledTicks > 0 && tick == round.lastSampled {
+		return
+	}
+	round.sampledTicks++
+	round.lastSampled = tick
+}
+
+// expectedTicks is how many sampled ticks the round's length calls for,
+// taking -interval-ms into account
+func expectedTicks(r roundRecord) int {
+	return int(math.Round(float64(r.endTick-r.startTick) / ticksPerSample(r.rate)))
+}
+
+// checkTickCount warns when a round was sampled noticeably less (or more)
+// often than its length says it should have been.
+func checkTickCount(r roundRecord) {
+	expected := expectedTicks(r)
+	if expected <= 0 {
+		return
+	}
+	if math.Abs(float64(r.sampledTicks-expected)) > float64(expected)*tickCountTolerance {
+		log.Printf("⚠️  Round %d has %d sampled ticks, expected about %d", r.number, r.sampledTicks, expected)
+	}
+}
+
+func clanName(team *common.TeamState) string {
+	if team == nil {
+		return ""
+	}
+	return team.ClanName()
+}
+
+func economyOf(team *common.TeamState) teamEconomy {
+	if team == nil {
+		return teamEconomy{}
+	}
+	bought := team.MoneySpentThisRound()
+	return teamEconomy{
+		carried: survivingValue[team.ID()],
+		bought:  bought,
+		kept:    max(team.FreezeTimeEndEquipmentValue()-bought, 0),
+	}
+}
+
+func recordSurvivors(team *common.TeamState) {
+	if team == nil {
+		return
+	}
+	value := 0
+	for _, player := range team.Members() {
+		if player != nil && player.IsAlive() {
+			value += player.EquipmentValueCurrent()
+		}
+	}
+	survivingValue[team.ID()] = value
+}
+
+func teamScore(team *common.TeamState) int {
+	if team == nil {
+		return 0
+	}
+	return team.Score()
+}
+
+func writeRoundRecord(r roundRecord) {
+	// rounds.json isn't a tracked writer, so apply -round-outcome here
+	if roundsJSON && (roundOutcomes == nil || outcomeState == outcomeKeep) {
+		addRoundSummary(r)
+	}
+	if roundsWriter == nil {
+		return
+	}
+
+	row := []string{
+		strconv.Itoa(r.number),
+		strconv.Itoa(r.startTick),
+		strconv.Itoa(r.endTick),
+		formatSeconds(gameTimeSeconds(r.startTick, r.rate)),
+		formatSeconds(gameTimeSeconds(r.endTick, r.rate)),
+		sideName(r.winner),
+		r.ctTeamName,
+		r.tTeamName,
+		strconv.Itoa(r.sampledTicks),
+		strconv.Itoa(expectedTicks(r)),
+		strconv.Itoa(r.rows),
+	}
+	row = append(row, r.ctGrenades.columns()...)
+	row = append(row, r.tGrenades.columns()...)
+	row = append(row, r.ctEconomy.columns()...)
+	row = append(row, r.tEconomy.columns()...)
+	row = append(row, r.clutch.columns(r.rate)...)
+	row = append(row, formatStreak(r.momentum))
+	roundsWriter.Write(row)
+}
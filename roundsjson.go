@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+)
+
+var (
+	roundsJSON     bool
+	roundSummaries []roundSummary
+)
+
+// roundSummary is the rounds.json form of a roundRecord
+type roundSummary struct {
+	Round     int    `json:"round"`
+	StartTick int    `json:"start_tick"`
+	EndTick   int    `json:"end_tick"`
+	Winner    string `json:"winner"`
+
+	Score struct {
+		CT int `json:"ct"`
+		T  int `json:"t"`
+	} `json:"score"`
+
+	CT teamSummary `json:"ct"`
+	T  teamSummary `json:"t"`
+
+	// Win streak after the round, positive for CT and negative for T
+	Momentum int `json:"momentum"`
+
+	// Set when one side was left 1vX (X >= 2)
+	Clutch *clutchSummary `json:"clutch"`
+
+	Ticks struct {
+		Sampled  int `json:"sampled"`
+		Expected int `json:"expected"`
+		Rows     int `json:"rows"`
+	} `json:"ticks"`
+}
+
+type clutchSummary struct {
+	StartTick int    `json:"start_tick"`
+	SteamID   string `json:"steamid"`
+	Opponents int    `json:"opponents"`
+}
+
+type teamSummary struct {
+	Name     string         `json:"name"`
+	Grenades map[string]int `json:"grenades_thrown"`
+	Economy  struct {
+		Carried  int    `json:"carried_value"`
+		Bought   int    `json:"buy_value"`
+		Decision string `json:"decision"`
+	} `json:"economy"`
+}
+
+func newTeamSummary(name string, grenades grenadeCounts, economy teamEconomy) teamSummary {
+	t := teamSummary{
+		Name: name,
+		Grenades: map[string]int{
+			"he":      grenades.he,
+			"flash":   grenades.flash,
+			"smoke":   grenades.smoke,
+			"molotov": grenades.molotov,
+			"decoy":   grenades.decoy,
+		},
+	}
+	t.Economy.Carried = economy.carried
+	t.Economy.Bought = economy.bought
+	t.Economy.Decision = economy.decision()
+	return t
+}
+
+func addRoundSummary(r roundRecord) {
+	s := roundSummary{
+		Round:     r.number,
+		StartTick: r.startTick,
+		EndTick:   r.endTick,
+		Winner:    sideName(r.winner),
+		CT:        newTeamSummary(r.ctTeamName, r.ctGrenades, r.ctEconomy),
+		T:         newTeamSummary(r.tTeamName, r.tGrenades, r.tEconomy),
+	}
+	s.Score.CT, s.Score.T = r.ctScore, r.tScore
+	s.Momentum = r.momentum
+	if c := r.clutch; c.player != nil {
+		s.Clutch = &clutchSummary{c.tick, playerSteamID(c.player), c.opponents}
+	}
+	s.Ticks.Sampled = r.sampledTicks
+	s.Ticks.Expected = expectedTicks(r)
+	s.Ticks.Rows = r.rows
+	roundSummaries = append(roundSummaries, s)
+}
+
+// writeRoundsJSON writes every summarized round to rounds.json as one array
+func writeRoundsJSON() {
+	summaries := roundSummaries
+	if summaries == nil {
+		summaries = []roundSummary{}
+	}
+	data, err := json.MarshalIndent(summaries, "", "  ")
+	if err != nil {
+		failOutput("encoding rounds.json", err)
+		return
+	}
+
+	file := openOutput(outputPath("rounds.json"))
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		failOutput("rounds.json", err)
+	}
+	closeOutput(file, nil)
+}
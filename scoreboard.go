@@ -0,0 +1,48 @@
+package main
+
+import (
+	"io"
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	exportScoreboard bool
+	scoreboardFile   io.WriteCloser
+	scoreboardWriter recordWriter
+)
+
+// registerScoreboardHandlers snapshots the scoreboard at every round end.
+// The values come straight from the player entities, so they match what the
+// in-game scoreboard showed at that moment.
+func registerScoreboardHandlers(p dem.Parser) {
+	scoreboardFile, scoreboardWriter = openCSV(outputPath("scoreboard.csv"), []string{
+		"round", "tick", "game_time_seconds", "player", "steamid", "side",
+		"kills", "deaths", "assists", "score", "mvps", "money",
+	})
+
+	p.RegisterEventHandler(func(e events.RoundEnd) {
+		if skippingRound || currentRound == 0 {
+			return
+		}
+		tick := p.GameState().IngameTick()
+		for _, player := range p.GameState().Participants().Playing() {
+			scoreboardWriter.Write([]string{
+				strconv.Itoa(currentRound),
+				strconv.Itoa(tick),
+				formatSeconds(gameTimeSeconds(tick, tickRate(p))),
+				player.Name,
+				strconv.FormatUint(player.SteamID64, 10),
+				sideName(player.Team),
+				strconv.Itoa(player.Kills()),
+				strconv.Itoa(player.Deaths()),
+				strconv.Itoa(player.Assists()),
+				strconv.Itoa(player.Score()),
+				strconv.Itoa(player.MVPs()),
+				strconv.Itoa(player.Money()),
+			})
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	skipFirstFrames  int
+	skipPerRound     bool
+	framesSinceStart int
+	framesSinceRound int
+
+	// roundStartDelay is -round-start-delay-ticks; roundStartTick is -1
+	// until the first round starts
+	roundStartDelay int
+	roundStartTick  = -1
+)
+
+func registerSettleHandlers(p dem.Parser) {
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		framesSinceRound = 0
+		roundStartTick = p.GameState().IngameTick()
+	})
+}
+
+// settling reports whether the sampled frame falls within the first
+// -skip-first-frames of the demo (or of the round with -skip-per-round),
+// where entities often still sit at the origin.
+func settling() bool {
+	framesSinceStart++
+	framesSinceRound++
+	if framesSinceStart <= skipFirstFrames {
+		return true
+	}
+	return skipPerRound && framesSinceRound <= skipFirstFrames
+}
+
+// inRoundStartDelay reports whether tick is within -round-start-delay-ticks
+// of the round start, while players are still being moved to their spawns.
+func inRoundStartDelay(tick int) bool {
+	return roundStartTick >= 0 && tick-roundStartTick < roundStartDelay
+}
@@ -0,0 +1,22 @@
+package main
+
+import "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+
+// startingSides is the side every player was first seen on in a real round,
+// so their rows can still be grouped after the halftime switch
+var startingSides = map[string]common.Team{}
+
+// startingSide returns the side player began the match on. Warmup doesn't
+// count, as teams are often shuffled before the match starts.
+func startingSide(player *common.Player) string {
+	key := playerKey(player)
+	side, ok := startingSides[key]
+	if !ok {
+		if currentRound == 0 || (player.Team != common.TeamTerrorists && player.Team != common.TeamCounterTerrorists) {
+			return ""
+		}
+		side = player.Team
+		startingSides[key] = side
+	}
+	return sideName(side)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+var (
+	exportSpotted bool
+	spottedFile   io.WriteCloser
+	spottedWriter recordWriter
+)
+
+func openSpotted() {
+	spottedFile, spottedWriter = openCSV(outputPath("spotted.csv"), []string{
+		"tick", "game_time_seconds", "spotter", "spotted",
+	})
+}
+
+// trackSpotted writes a row for every living player another has spotted on
+// the sampled tick. It's the game's own spotted-by mask, which the parser
+// decodes per player slot (including the second mask word for slots past
+// 32), so unlike -visibility walls and smokes are taken into account.
+func trackSpotted(ctx tickContext, players []*common.Player) {
+	for _, spotted := range players {
+		if !spotted.IsAlive() {
+			continue
+		}
+		for _, spotter := range players {
+			if spotter == spotted || !spotter.IsAlive() || !spotted.IsSpottedBy(spotter) {
+				continue
+			}
+			spottedWriter.Write([]string{
+				strconv.Itoa(ctx.tick),
+				formatSeconds(ctx.gameTime),
+				strconv.FormatUint(spotter.SteamID64, 10),
+				strconv.FormatUint(spotted.SteamID64, 10),
+			})
+		}
+	}
+}
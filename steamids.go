@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+var (
+	// expectedSteamIDs is the -expected-steamids allow list, nil without one
+	expectedSteamIDs map[uint64]bool
+	failOnUnexpected bool
+	// unexpectedPlayers are the names seen for SteamIDs not on the list in
+	// the current demo; unexpectedDemos counts demos that had any
+	unexpectedPlayers = map[uint64]string{}
+	unexpectedDemos   int
+)
+
+// loadExpectedSteamIDs reads one SteamID64 per line. Blank lines and text
+// after a # are ignored, so the file can name the players.
+func loadExpectedSteamIDs(path string) (map[uint64]bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ids := map[uint64]bool{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid steamid %q", line, text)
+		}
+		ids[id] = true
+	}
+	return ids, scanner.Err()
+}
+
+// checkSteamIDs notes players who aren't on the allow list. Bots have no
+// SteamID and are left alone.
+func checkSteamIDs(players []*common.Player) {
+	for _, player := range players {
+		id := player.SteamID64
+		if id == 0 || expectedSteamIDs[id] {
+			continue
+		}
+		if _, seen := unexpectedPlayers[id]; !seen {
+			unexpectedPlayers[id] = player.Name
+		}
+	}
+}
+
+// reportUnexpectedSteamIDs lists the demo's unexpected players, if any
+func reportUnexpectedSteamIDs() {
+	if len(unexpectedPlayers) == 0 {
+		return
+	}
+	unexpectedDemos++
+
+	ids := make([]uint64, 0, len(unexpectedPlayers))
+	for id := range unexpectedPlayers {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var list []string
+	for _, id := range ids {
+		list = append(list, fmt.Sprintf("%s (%d)", unexpectedPlayers[id], id))
+	}
+	log.Printf("⚠️  %d players in %s are not in -expected-steamids: %s", len(ids), demoName, strings.Join(list, ", "))
+	metaNotes = append(metaNotes, "players not in -expected-steamids: "+strings.Join(list, ", "))
+}
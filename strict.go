@@ -0,0 +1,46 @@
+package main
+
+import (
+	"log"
+
+	"github.com/golang/geo/r3"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+var (
+	strict         bool
+	skippedRows    int
+	warnedSteamIDs = map[uint64]bool{}
+)
+
+// missingData returns why a player's row can't be trusted, or "" if it looks
+// complete. Uninitialized entities typically report a (0,0,0) position.
+func missingData(player *common.Player) string {
+	switch {
+	case player.Entity == nil:
+		return "no entity"
+	case player.Name == "":
+		return "no name"
+	case player.Position() == r3.Vector{}:
+		return "position is (0,0,0)"
+	}
+	return ""
+}
+
+// hasPosition tells a real position at the origin apart from a player that
+// has no entity or hasn't spawned yet, which also reads as (0,0,0).
+// -strict drops such rows altogether.
+func hasPosition(player *common.Player) bool {
+	return player.Entity != nil && player.Position() != r3.Vector{}
+}
+
+// skipIncompleteRow counts a dropped row, warning once per player so broken
+// demos don't flood the log.
+func skipIncompleteRow(tick int, player *common.Player, reason string) {
+	skippedRows++
+	if warnedSteamIDs[player.SteamID64] {
+		return
+	}
+	warnedSteamIDs[player.SteamID64] = true
+	log.Printf("⚠️  Skipping row for %q at tick %d: %s", player.Name, tick, reason)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+// summaryOnly skips the tick files and event exporters and only writes
+// meta.json, rounds.csv and player_stats.csv
+var summaryOnly bool
+
+// checkSummaryOnly turns on the outputs -summary-only consists of and
+// rejects flags asking for any other
+func checkSummaryOnly(fs *flag.FlagSet) error {
+	conflicts := append([]string{
+		"split-rounds", "split-players", "round-window", "visibility", "reactions",
+		"spotted", "team-aggregate-positions", "activity-summary", "text-log",
+		"compact-names", "round-summary-json",
+	}, eventFlags...)
+	given := givenFlags(fs)
+	for _, name := range conflicts {
+		if name == "rounds-csv" || !given[name] {
+			continue
+		}
+		if v := fs.Lookup(name).Value.String(); v != "false" && v != "0" {
+			return fmt.Errorf("-summary-only can't be combined with -%s", name)
+		}
+	}
+	exportRounds = true
+	return nil
+}
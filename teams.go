@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+var (
+	exportTeamPositions bool
+	teamPositionsFile   io.WriteCloser
+	teamPositionsWriter recordWriter
+)
+
+func openTeamPositions() {
+	teamPositionsFile, teamPositionsWriter = openCSV(outputPath("team_positions.csv"), []string{
+		"tick", "game_time_seconds",
+		"ct_alive", "ct_centroid_x", "ct_centroid_y", "ct_spread",
+		"t_alive", "t_centroid_x", "t_centroid_y", "t_spread",
+	})
+}
+
+// trackTeamPositions writes each side's centroid of living players on the
+// map plane and their spread, the root mean square distance from it
+func trackTeamPositions(ctx tickContext, players []*common.Player) {
+	row := []string{strconv.Itoa(ctx.tick), formatSeconds(ctx.gameTime)}
+	row = append(row, teamFormation(players, common.TeamCounterTerrorists)...)
+	row = append(row, teamFormation(players, common.TeamTerrorists)...)
+	teamPositionsWriter.Write(row)
+}
+
+// teamFormation returns alive, centroid_x, centroid_y and spread for team,
+// the last three empty with nobody alive
+func teamFormation(players []*common.Player, team common.Team) []string {
+	var xs, ys []float64
+	for _, player := range players {
+		if player.Team == team && player.IsAlive() {
+			pos := player.Position()
+			xs = append(xs, pos.X)
+			ys = append(ys, pos.Y)
+		}
+	}
+	n := len(xs)
+	if n == 0 {
+		return []string{"0", "", "", ""}
+	}
+
+	var cx, cy float64
+	for i := range xs {
+		cx += xs[i]
+		cy += ys[i]
+	}
+	cx /= float64(n)
+	cy /= float64(n)
+
+	var sq float64
+	for i := range xs {
+		sq += (xs[i]-cx)*(xs[i]-cx) + (ys[i]-cy)*(ys[i]-cy)
+	}
+	return []string{
+		strconv.Itoa(n),
+		fmt.Sprintf("%.2f", cx),
+		fmt.Sprintf("%.2f", cy),
+		fmt.Sprintf("%.2f", math.Sqrt(sq/float64(n))),
+	}
+}
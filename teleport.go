@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/golang/geo/r3"
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+type positionSample struct {
+	pos  r3.Vector
+	tick int
+}
+
+var (
+	teleportSpeed float64
+	lastSamples   = map[string]positionSample{}
+)
+
+func registerTeleportHandlers(p dem.Parser) {
+	// Respawning at round start isn't a teleport
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		clear(lastSamples)
+	})
+}
+
+// isTeleport reports whether the player moved further since their previous
+// sample than -teleport-speed allows over the elapsed time. Freeze time is
+// exempt since that's when players get moved to their spawns.
+func isTeleport(ctx tickContext, player *common.Player, alive bool) bool {
+	key := playerKey(player)
+	if !alive {
+		delete(lastSamples, key)
+		return false
+	}
+
+	pos := player.Position()
+	prev, ok := lastSamples[key]
+	if ok && prev.tick == ctx.tick {
+		// Another frame of the same tick, nothing has elapsed to compare
+		return false
+	}
+	lastSamples[key] = positionSample{pos: pos, tick: ctx.tick}
+	if !ok || roundPhase == phaseFreezetime {
+		return false
+	}
+
+	elapsed := float64(ctx.tick-prev.tick) / ctx.rate
+	return pos.Distance(prev.pos) > teleportSpeed*elapsed
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	textLog     bool
+	textLogFile io.WriteCloser
+	textLogOut  recordWriter
+)
+
+// lineWriter writes each record's first field as a line, so the text log
+// goes through trackWriter like the CSV outputs: it is flushed with
+// -flush-every and its rounds are filtered by -round-outcome.
+type lineWriter struct {
+	w    *bufio.Writer
+	name string
+}
+
+func (l *lineWriter) Write(record []string) error {
+	l.w.WriteString(record[0])
+	return l.w.WriteByte('\n')
+}
+
+func (l *lineWriter) Flush() {
+	if err := l.w.Flush(); err != nil {
+		failOutput(l.name, err)
+	}
+}
+
+// registerTextLogHandlers writes a readable, chronological match report to
+// events.log, mirroring what the CSV exporters capture.
+func registerTextLogHandlers(p dem.Parser) {
+	file := openOutput(outputPath("events.log"))
+	textLogFile = file
+	textLogOut = trackWriter(&lineWriter{bufio.NewWriter(file), "events.log"})
+
+	logf := func(format string, args ...interface{}) {
+		tick := p.GameState().IngameTick()
+		secs := gameTimeSeconds(tick, tickRate(p))
+		mins := int(secs) / 60
+		stamp := fmt.Sprintf("[%02d:%06.3f] ", mins, secs-float64(mins*60))
+		textLogOut.Write([]string{stamp + fmt.Sprintf(format, args...)})
+	}
+	live := func() bool {
+		return !skippingRound && currentRound > 0
+	}
+
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		if live() {
+			logf("Round %d started", currentRound)
+		}
+	})
+	p.RegisterEventHandler(func(e events.Kill) {
+		if !live() {
+			return
+		}
+		weapon := "world"
+		if e.Weapon != nil {
+			weapon = e.Weapon.String()
+		}
+		line := fmt.Sprintf("%s killed %s with %s", logName(e.Killer), logName(e.Victim), weapon)
+		if e.IsHeadshot {
+			line += " (headshot)"
+		}
+		if e.Assister != nil {
+			line += ", assisted by " + logName(e.Assister)
+		}
+		logf("%s", line)
+	})
+	p.RegisterEventHandler(func(e events.BombPlanted) {
+		if live() {
+			logf("%s planted the bomb at %s", logName(e.Player), siteName(e.Site))
+		}
+	})
+	p.RegisterEventHandler(func(e events.BombDefused) {
+		if live() {
+			logf("%s defused the bomb", logName(e.Player))
+		}
+	})
+	p.RegisterEventHandler(func(e events.BombExplode) {
+		if live() {
+			logf("The bomb exploded at %s", siteName(e.Site))
+		}
+	})
+	p.RegisterEventHandler(func(e events.RoundEnd) {
+		if !live() {
+			return
+		}
+		reason := roundEndReasons[e.Reason]
+		if reason == "" {
+			reason = fmt.Sprintf("reason %d", e.Reason)
+		}
+		logf("Round %d over: %s won (%s)", currentRound, sideName(e.Winner), reason)
+	})
+}
+
+// logName is a player's name followed by their side, e.g. "name (CT)"
+func logName(player *common.Player) string {
+	if player == nil {
+		return "world"
+	}
+	return fmt.Sprintf("%s (%s)", player.Name, sideName(player.Team))
+}
+
+func siteName(site rune) string {
+	if site == 0 {
+		return "an unknown site"
+	}
+	return string(site)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"slices"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+// -throttle-memory targets keeping the process under throttleMemoryLimit
+// for a single demo at a time; it is a soft limit, so the GC works harder
+// as it gets close instead of the process failing.
+const (
+	throttleMemoryLimit = 512 << 20
+	throttleFlushTicks  = 64
+)
+
+var throttleMemory bool
+
+// applyThrottleMemory sets the defaults behind -throttle-memory: a soft
+// memory limit (unless GOMEMLIMIT is set), synchronous parsing instead of a
+// message queue and flushing the outputs every second of demo. Explicit
+// -msg-queue-size and -flush-every values win.
+func applyThrottleMemory(queueSet, flushSet bool) {
+	if os.Getenv("GOMEMLIMIT") == "" {
+		debug.SetMemoryLimit(throttleMemoryLimit)
+	}
+	if !queueSet {
+		msgQueueSize = 0
+	}
+	if !flushSet {
+		flushEvery = throttleFlushTicks
+	}
+	fmt.Printf("🪶 Throttling memory to about %d MiB\n", throttleMemoryLimit>>20)
+}
+
+// registerThrottleHandlers drops the per-player tracking state of players
+// who leave, which otherwise piles up on long demos with many reconnects.
+// Match-long aggregates are kept, but a leaver gets no activity_summary.csv
+// row for the round they left in.
+func registerThrottleHandlers(p dem.Parser) {
+	p.RegisterEventHandler(func(e events.PlayerDisconnected) {
+		if e.Player == nil {
+			return
+		}
+		key := playerKey(e.Player)
+		delete(lastAlivePose, key)
+		delete(lastPositions, key)
+		delete(lastYaw, key)
+		delete(lastSamples, key)
+		delete(lastMovement, key)
+		delete(lastZoom, key)
+		delete(lastMoney, key)
+		delete(moneyReasons, key)
+		delete(flashedBy, key)
+		delete(sprays, key)
+		delete(prevPoses, key)
+		if _, ok := activities[key]; ok {
+			delete(activities, key)
+			activityOrder = slices.DeleteFunc(activityOrder, func(k string) bool { return k == key })
+		}
+		for pair := range inContact {
+			if pair[0] == e.Player || pair[1] == e.Player {
+				delete(inContact, pair)
+				delete(pendingContacts, pair)
+			}
+		}
+		// recentDamage is keyed by SteamID, which all bots share
+		if e.Player.SteamID64 != 0 {
+			delete(recentDamage, e.Player.SteamID64)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"io"
+	"strconv"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+// utilityDamage is the grenade damage a player dealt to enemies
+type utilityDamage struct {
+	he, fire int
+}
+
+var (
+	exportUtility bool
+	utilityFile   io.WriteCloser
+	utilityWriter recordWriter
+	roundUtility  = map[string]utilityDamage{}
+	matchUtility  = map[string]utilityDamage{}
+)
+
+func registerUtilityHandlers(p dem.Parser) {
+	utilityFile, utilityWriter = openCSV(outputPath("utility_damage.csv"), []string{
+		"round", "player", "steamid",
+		"he_damage", "fire_damage", "total_he_damage", "total_fire_damage",
+	})
+
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		clear(roundUtility)
+	})
+
+	p.RegisterEventHandler(func(e events.PlayerHurt) {
+		if skippingRound || currentRound == 0 || e.Weapon == nil || e.Attacker == nil {
+			return
+		}
+		if e.Player == nil {
+			return
+		}
+		if !isEnemy(e.Attacker, e.Player) {
+			return
+		}
+		key := playerKey(e.Attacker)
+		r, m := roundUtility[key], matchUtility[key]
+		switch e.Weapon.Type {
+		case common.EqHE:
+			r.he += e.HealthDamageTaken
+			m.he += e.HealthDamageTaken
+		case common.EqMolotov, common.EqIncendiary:
+			r.fire += e.HealthDamageTaken
+			m.fire += e.HealthDamageTaken
+		default:
+			return
+		}
+		roundUtility[key], matchUtility[key] = r, m
+	})
+
+	p.RegisterEventHandler(func(e events.RoundEnd) {
+		if skippingRound || currentRound == 0 {
+			return
+		}
+		for _, player := range p.GameState().Participants().Playing() {
+			key := playerKey(player)
+			r, m := roundUtility[key], matchUtility[key]
+			utilityWriter.Write([]string{
+				strconv.Itoa(currentRound),
+				player.Name,
+				strconv.FormatUint(player.SteamID64, 10),
+				strconv.Itoa(r.he),
+				strconv.Itoa(r.fire),
+				strconv.Itoa(m.he),
+				strconv.Itoa(m.fire),
+			})
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+var (
+	unwrapView bool
+	lastYaw    = map[string]float32{}
+)
+
+func registerViewHandlers(p dem.Parser) {
+	// Start every round from the raw angle so values don't drift unbounded
+	p.RegisterEventHandler(func(e events.RoundStart) {
+		clear(lastYaw)
+	})
+}
+
+// unwrapYaw shifts yaw by whole turns so it stays within 180° of the player's
+// previous sample, removing the jump when the raw angle wraps around.
+func unwrapYaw(key string, yaw float32) float32 {
+	if prev, ok := lastYaw[key]; ok {
+		for yaw-prev > 180 {
+			yaw -= 360
+		}
+		for yaw-prev < -180 {
+			yaw += 360
+		}
+	}
+	lastYaw[key] = yaw
+	return yaw
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+var (
+	exportVisibility bool
+	// visibilityFOV is the horizontal field of view in degrees; 106 is the
+	// game's 90° at 4:3 stretched to 16:9
+	visibilityFOV     float64
+	visibilityMaxDist float64
+	visibilityFile    io.WriteCloser
+	visibilityWriter  recordWriter
+)
+
+func openVisibility() {
+	visibilityFile, visibilityWriter = openCSV(outputPath("visibility.csv"), []string{
+		"tick", "game_time_seconds", "observer", "target", "can_see", "spotted", "distance",
+	})
+}
+
+// trackVisibility writes one row per living observer and living enemy.
+// can_see only checks that the target is inside the observer's field of view
+// and -visibility-max-dist, so enemies behind walls or smokes count as seen;
+// spotted is the game's own spotted state, which does account for walls.
+func trackVisibility(ctx tickContext, players []*common.Player) {
+	for _, observer := range players {
+		if !observer.IsAlive() {
+			continue
+		}
+		for _, target := range players {
+			if !target.IsAlive() || !isEnemy(observer, target) {
+				continue
+			}
+			visibilityWriter.Write([]string{
+				strconv.Itoa(ctx.tick),
+				formatSeconds(ctx.gameTime),
+				strconv.FormatUint(observer.SteamID64, 10),
+				strconv.FormatUint(target.SteamID64, 10),
+				boolToIntString(inView(observer, target)),
+				boolToIntString(target.IsSpottedBy(observer)),
+				fmt.Sprintf("%.2f", target.PositionEyes().Distance(observer.PositionEyes())),
+			})
+		}
+	}
+}
+
+// inView reports whether target is within observer's field of view and
+// -visibility-max-dist, ignoring walls
+func inView(observer, target *common.Player) bool {
+	toTarget := target.PositionEyes().Sub(observer.PositionEyes())
+	if visibilityMaxDist > 0 && toTarget.Norm() > visibilityMaxDist {
+		return false
+	}
+	// Compare yaw only, the vertical FOV is rarely what hides someone
+	toTarget.Z = 0
+	return angleBetween(viewVector(observer.ViewDirectionX(), 0), toTarget) <= visibilityFOV/2
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/golang/geo/r3"
+	dem "github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/events"
+)
+
+type playerSample struct {
+	name         string
+	pos          r3.Vector
+	viewX, viewY float32
+}
+
+type tickSample struct {
+	tick     int
+	gameTime float64
+	players  []playerSample
+}
+
+// sampleRing keeps the most recent tick samples so a kill can be written
+// together with the ticks that led up to it.
+type sampleRing struct {
+	buf  []tickSample
+	next int
+	full bool
+}
+
+func newSampleRing(size int) *sampleRing {
+	return &sampleRing{buf: make([]tickSample, size)}
+}
+
+func (r *sampleRing) push(s tickSample) {
+	r.buf[r.next] = s
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// each calls fn for every buffered sample, oldest first.
+func (r *sampleRing) each(fn func(tickSample)) {
+	if r.full {
+		for _, s := range r.buf[r.next:] {
+			fn(s)
+		}
+	}
+	for _, s := range r.buf[:r.next] {
+		fn(s)
+	}
+}
+
+// killWindow is a kill whose trailing ticks are still being captured
+type killWindow struct {
+	id       int
+	killTick int
+	lastTick int
+	killer   string
+	victim   string
+}
+
+var (
+	windowTicks    int
+	windowFile     io.WriteCloser
+	windowWriter   recordWriter
+	windowHistory  *sampleRing
+	pendingWindows []*killWindow
+	killCount      int
+)
+
+func registerWindowHandlers(p dem.Parser) {
+	windowFile, windowWriter = openCSV(outputPath("kill_windows.csv"), []string{
+		"kill_id", "kill_tick", "killer", "victim",
+		"tick", "game_time_seconds", "offset", "player_name",
+		"pos_x", "pos_y", "pos_z",
+		"view_dir_x", "view_dir_y",
+	})
+	windowHistory = newSampleRing(windowTicks + 1)
+
+	p.RegisterEventHandler(func(e events.Kill) {
+		if skippingRound {
+			return
+		}
+		killCount++
+		tick := p.GameState().IngameTick()
+
+		w := &killWindow{
+			id:       killCount,
+			killTick: tick,
+			lastTick: tick - windowTicks - 1,
+			killer:   playerName(e.Killer),
+			victim:   playerName(e.Victim),
+		}
+
+		// Backfill the ticks before the kill from the ring buffer
+		windowHistory.each(func(s tickSample) {
+			if s.tick >= tick-windowTicks {
+				writeWindowSample(w, s)
+			}
+		})
+		pendingWindows = append(pendingWindows, w)
+	})
+}
+
+// captureWindowTick records the sampled tick and forwards it to every kill
+// window that is still open.
+func captureWindowTick(ctx tickContext, players []*common.Player) {
+	tick := ctx.tick
+	s := tickSample{tick: tick, gameTime: ctx.gameTime, players: make([]playerSample, 0, len(players))}
+	for _, player := range players {
+		s.players = append(s.players, playerSample{
+			name:  player.Name,
+			pos:   player.Position(),
+			viewX: player.ViewDirectionX(),
+			viewY: player.ViewDirectionY(),
+		})
+	}
+	windowHistory.push(s)
+
+	open := pendingWindows[:0]
+	for _, w := range pendingWindows {
+		if tick > w.lastTick && tick <= w.killTick+windowTicks {
+			writeWindowSample(w, s)
+		}
+		if tick < w.killTick+windowTicks {
+			open = append(open, w)
+		}
+	}
+	pendingWindows = open
+}
+
+func writeWindowSample(w *killWindow, s tickSample) {
+	for _, ps := range s.players {
+		windowWriter.Write([]string{
+			strconv.Itoa(w.id),
+			strconv.Itoa(w.killTick),
+			w.killer,
+			w.victim,
+			strconv.Itoa(s.tick),
+			formatSeconds(s.gameTime),
+			strconv.Itoa(s.tick - w.killTick),
+			ps.name,
+			fmt.Sprintf("%.2f", ps.pos.X),
+			fmt.Sprintf("%.2f", ps.pos.Y),
+			fmt.Sprintf("%.2f", ps.pos.Z),
+			fmt.Sprintf("%.4f", ps.viewX),
+			fmt.Sprintf("%.4f", ps.viewY),
+		})
+	}
+	w.lastTick = s.tick
+}
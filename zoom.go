@@ -0,0 +1,68 @@
+package main
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/markus-wa/demoinfocs-golang/v4/pkg/demoinfocs/common"
+)
+
+var (
+	exportZoom bool
+	zoomFile   io.WriteCloser
+	zoomWriter recordWriter
+	lastZoom   = map[string]common.ZoomLevel{}
+)
+
+func openZoomEvents() {
+	zoomFile, zoomWriter = openCSV(outputPath("zoom_events.csv"), []string{
+		"tick", "game_time_seconds", "player", "weapon", "zoom_transition", "zoom_level",
+	})
+}
+
+// trackZoom compares each player's zoom level with the previous sampled tick
+// and writes a row for every change. Switching away from a scoped weapon
+// shows up as a scope_out.
+func trackZoom(ctx tickContext, players []*common.Player) {
+	for _, player := range players {
+		key := playerKey(player)
+		if !player.IsAlive() {
+			delete(lastZoom, key)
+			continue
+		}
+
+		weapon := player.ActiveWeapon()
+		now := common.ZoomNone
+		if weapon != nil {
+			now = weapon.ZoomLevel()
+		}
+		prev, seen := lastZoom[key]
+		lastZoom[key] = now
+		if !seen || now == prev {
+			continue
+		}
+
+		var transition string
+		switch {
+		case prev == common.ZoomNone:
+			transition = "scope_in"
+		case now == common.ZoomNone:
+			transition = "scope_out"
+		default:
+			transition = pick(now > prev, "zoom_in", "zoom_out")
+		}
+
+		var weaponName string
+		if weapon != nil {
+			weaponName = weapon.String()
+		}
+		zoomWriter.Write([]string{
+			strconv.Itoa(ctx.tick),
+			formatSeconds(ctx.gameTime),
+			player.Name,
+			weaponName,
+			transition,
+			strconv.Itoa(int(now)),
+		})
+	}
+}